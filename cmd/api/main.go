@@ -13,8 +13,10 @@ import (
 	"github.com/user/project/internal/api"
 	"github.com/user/project/internal/contract"
 	"github.com/user/project/internal/db"
+	"github.com/user/project/internal/football"
 	"github.com/user/project/internal/notification"
 	"github.com/user/project/internal/s3"
+	"github.com/user/project/internal/scheduler"
 	"github.com/user/project/internal/syncer"
 	"github.com/user/project/internal/terrors"
 	"gopkg.in/yaml.v3"
@@ -43,13 +45,29 @@ type Config struct {
 	} `yaml:"aws"`
 	AssetsURL   string `yaml:"assets_url"`
 	FootballAPI struct {
-		APIKey  string `yaml:"api_key"`
-		BaseURL string `yaml:"base_url"`
+		APIKey   string `yaml:"api_key"`
+		BaseURL  string `yaml:"base_url"`
+		Provider string `yaml:"provider"` // football-data (default) or file
+		DataDir  string `yaml:"data_dir"` // Fixtures and results for the file provider
+		// Mode "record" saves football-data.org responses to FixturesDir, "replay" serves them offline
+		Mode        string `yaml:"mode"`
+		FixturesDir string `yaml:"fixtures_dir"`
 	} `yaml:"football_api"`
-	OpenAIKey         string `yaml:"openai_key"`
-	TelegramChannelID int64  `yaml:"telegram_channel_id"`
-	BotWebApp         string `yaml:"bot_web_app"`
-	ExternalURL       string `yaml:"external_url"`
+	OpenAIKey         string   `yaml:"openai_key"`
+	TelegramChannelID int64    `yaml:"telegram_channel_id"`
+	BotWebApp         string   `yaml:"bot_web_app"`
+	ExternalURL       string   `yaml:"external_url"`
+	Timezone          string   `yaml:"timezone"` // League timezone, defaults to Europe/Moscow
+	Admins            []string `yaml:"admins"`   // User IDs allowed to use admin endpoints
+	Competitions      []struct {
+		Code         string `yaml:"code"`
+		Name         string `yaml:"name"`
+		Emblem       string `yaml:"emblem"`
+		Area         string `yaml:"area"`
+		SyncPriority int    `yaml:"sync_priority"`
+	} `yaml:"competitions"` // Seeded into the database on start, managed through the admin API afterwards
+	Jobs         map[string]string `yaml:"jobs"`          // Job name to cron expression, "@every <duration>" or "off"
+	ReminderLead time.Duration     `yaml:"reminder_lead"` // How long before kickoff to remind about unpredicted matches, e.g. 1h
 }
 
 func ReadConfig(filePath string) (*Config, error) {
@@ -65,9 +83,53 @@ func ReadConfig(filePath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to decode config file: %w", err)
 	}
 
+	if cfg.Timezone == "" {
+		cfg.Timezone = "Europe/Moscow"
+	}
+
 	return &cfg, nil
 }
 
+// defaultCompetitions are synced when the config doesn't list any
+var defaultCompetitions = []db.Competition{
+	{Code: "PL", Name: "Premier League"},
+	{Code: "PD", Name: "Primera Division"},
+	{Code: "FL1", Name: "Ligue 1"},
+	{Code: "SA", Name: "Serie A"},
+	{Code: "BL1", Name: "Bundesliga"},
+	{Code: "CL", Name: "UEFA Champions League"},
+}
+
+func competitionsFromConfig(cfg *Config) []db.Competition {
+	if len(cfg.Competitions) == 0 {
+		competitions := make([]db.Competition, len(defaultCompetitions))
+		for i, c := range defaultCompetitions {
+			c.Enabled = true
+			competitions[i] = c
+		}
+		return competitions
+	}
+
+	competitions := make([]db.Competition, 0, len(cfg.Competitions))
+	for _, c := range cfg.Competitions {
+		competition := db.Competition{
+			Code:         c.Code,
+			Name:         c.Name,
+			Enabled:      true,
+			SyncPriority: c.SyncPriority,
+		}
+		if c.Emblem != "" {
+			competition.Emblem = &c.Emblem
+		}
+		if c.Area != "" {
+			competition.Area = &c.Area
+		}
+		competitions = append(competitions, competition)
+	}
+
+	return competitions
+}
+
 func ValidateConfig(cfg *Config) error {
 	validate := validator.New()
 	return validate.Struct(cfg)
@@ -200,109 +262,145 @@ func gracefulShutdown(e *echo.Echo, done chan<- bool) {
 	done <- true
 }
 
-func startSyncer(ctx context.Context, sync *syncer.Syncer) {
-	log.Println("Starting initial sync process...")
-	if err := sync.SyncTeams(ctx); err != nil {
-		log.Printf("Initial team sync failed: %v", err)
-	}
-
-	ticker := time.NewTicker(3 * time.Minute)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			log.Println("Starting sync process...")
-			if err := sync.SyncMatches(ctx); err != nil {
-				log.Printf("Failed to sync matches: %v", err)
-			}
-
-			if err := sync.ProcessPredictions(ctx); err != nil {
-				log.Printf("Failed to process predictions: %v", err)
-			}
-
-			if err := sync.ManageSeasons(ctx); err != nil {
-				log.Printf("Initial season sync failed: %v", err)
-			}
-		case <-ctx.Done():
-			log.Println("Stopping syncer...")
-			return
-		}
-	}
+// defaultJobSchedules are used for jobs missing from the config's jobs section.
+// "off" or an empty schedule disables a job, except match_poll which then adapts to kickoff times.
+var defaultJobSchedules = map[string]string{
+	"match_poll":           "",
+	"match_reconcile":      "@every 6h",
+	"popularity":           "@every 30m",
+	"notifications":        "0 10 * * *",
+	"kickoff_reminders":    "@every 5m",
+	"queued_notifications": "@every 5m",
+	"leaderboard_snapshot": "5 0 * * *",
+	"weekly_recap":         "",
 }
 
-func startNotificationJob(ctx context.Context, sync *syncer.Syncer) {
-	location, err := time.LoadLocation("Europe/Moscow")
-	if err != nil {
-		log.Fatalf("Failed to load Moscow timezone: %v", err)
+// registerJobs adds the background jobs to the scheduler with schedules from the config
+func registerJobs(ctx context.Context, sched *scheduler.Scheduler, sync *syncer.Syncer, cfg *Config, location *time.Location) error {
+	jobs := []scheduler.Job{
+		{
+			// Poll every minute around live matches and back off when nothing is on
+			Name: "match_poll",
+			Schedule: scheduler.ScheduleFunc(func(now time.Time) time.Time {
+				wait, _, err := sync.NextPoll(ctx)
+				if err != nil {
+					log.Printf("Failed to plan next sync: %v", err)
+				}
+				return now.Add(wait)
+			}),
+			Run: func(ctx context.Context) error {
+				_, live, err := sync.NextPoll(ctx)
+				if err != nil {
+					log.Printf("Failed to plan next sync: %v", err)
+				}
+
+				if live {
+					err = sync.SyncLiveMatches(ctx)
+				} else {
+					err = sync.SyncMatches(ctx)
+				}
+				if err != nil {
+					log.Printf("Failed to sync matches: %v", err)
+				}
+
+				if err := sync.ProcessPredictions(ctx); err != nil {
+					log.Printf("Failed to process predictions: %v", err)
+				}
+
+				return sync.ManageSeasons(ctx)
+			},
+		},
+		{
+			// Full sync picking up whatever the incremental polling missed,
+			// also run first thing so a fresh database gets teams and fixtures
+			Name:       "match_reconcile",
+			RunOnStart: true,
+			Run: func(ctx context.Context) error {
+				if err := sync.SyncTeams(ctx); err != nil {
+					log.Printf("Failed to sync teams: %v", err)
+				}
+
+				if err := sync.ReconcileMatches(ctx); err != nil {
+					log.Printf("Failed to reconcile matches: %v", err)
+				}
+
+				if err := sync.SyncStandings(ctx); err != nil {
+					log.Printf("Failed to sync standings: %v", err)
+				}
+
+				if err := sync.RefreshPopularity(ctx); err != nil {
+					log.Printf("Failed to refresh match popularity: %v", err)
+				}
+
+				return sync.PruneJobRuns(ctx)
+			},
+		},
+		{
+			// Rescores upcoming matches as predictions come in,
+			// so the most popular match of the day follows what users actually pick
+			Name: "popularity",
+			Run:  sync.RefreshPopularity,
+		},
+		{
+			Name: "notifications",
+			Run:  sync.SendMatchNotification,
+		},
+		{
+			// Reminders are logged per match, running often only narrows the gap to the lead time
+			Name: "kickoff_reminders",
+			Run:  sync.SendKickoffReminders,
+		},
+		{
+			// Quiet hours end on the minute, a few minutes late is fine
+			Name: "queued_notifications",
+			Run:  sync.SendQueuedNotifications,
+		},
+		{
+			// Snapshots are idempotent per day, so catch up right away after a restart
+			Name:       "leaderboard_snapshot",
+			RunOnStart: true,
+			Run:        sync.SnapshotLeaderboards,
+		},
+		{
+			Name: "weekly_recap",
+			Run:  sync.SendWeeklyRecap,
+		},
 	}
 
-	for {
-		now := time.Now().In(location) // Get current time in Moscow timezone
-		nextRun := time.Date(now.Year(), now.Month(), now.Day(), 10, 00, 0, 0, location)
-
-		// If it's already past 10 AM MSK today, schedule for tomorrow
-		if now.After(nextRun) {
-			nextRun = nextRun.Add(24 * time.Hour)
+	for _, job := range jobs {
+		spec, ok := cfg.Jobs[job.Name]
+		if !ok {
+			spec = defaultJobSchedules[job.Name]
 		}
 
-		waitDuration := time.Until(nextRun)
-		log.Printf("Next notification job scheduled at: %v (Moscow Time)", nextRun)
-
-		timer := time.NewTimer(waitDuration)
-
-		select {
-		case <-timer.C:
-			log.Println("Running notification job at 10 AM Moscow Time...")
-
-			if err := sync.SendMatchNotification(ctx); err != nil {
-				log.Printf("Failed to send match notifications: %v", err)
+		if spec == "off" {
+			job.Schedule = nil
+		} else if spec != "" {
+			schedule, err := scheduler.Parse(spec, location)
+			if err != nil {
+				return fmt.Errorf("invalid schedule for job %s: %w", job.Name, err)
 			}
-		case <-ctx.Done():
-			log.Println("Stopping notification job...")
-			timer.Stop()
-			return
+			job.Schedule = schedule
 		}
-	}
-}
-
-func startWeeklyRecapJob(ctx context.Context, sync *syncer.Syncer) {
-	location, err := time.LoadLocation("Europe/Moscow")
-	if err != nil {
-		log.Fatalf("Failed to load Moscow timezone: %v", err)
-	}
-
-	if err := sync.SendWeeklyRecap(ctx); err != nil {
-		log.Printf("Failed to send weekly recap: %v", err)
-	}
 
-	for {
-		now := time.Now().In(location)
-		// Находим следующий понедельник в 10:00 MSK
-		daysUntilMonday := (8 - int(now.Weekday())) % 7 // 1 - Monday, 0 - Sunday
-		if daysUntilMonday == 0 && now.Hour() >= 10 {
-			daysUntilMonday = 7 // Если сегодня понедельник и уже после 10:00, ждем следующего
+		if job.Schedule == nil {
+			log.Printf("Job %s is disabled", job.Name)
+			continue
 		}
-		nextRun := time.Date(now.Year(), now.Month(), now.Day(), 10, 0, 0, 0, location).
-			AddDate(0, 0, daysUntilMonday)
 
-		waitDuration := time.Until(nextRun)
-		log.Printf("Next weekly recap job scheduled at: %v (Moscow Time)", nextRun)
+		sched.Register(job)
+	}
 
-		timer := time.NewTimer(waitDuration)
+	return nil
+}
 
-		select {
-		case <-timer.C:
-			log.Println("Running weekly recap job at 10 AM Moscow Time...")
-			if err := sync.SendWeeklyRecap(ctx); err != nil {
-				log.Printf("Failed to send weekly recap: %v", err)
-			}
-		case <-ctx.Done():
-			log.Println("Stopping weekly recap job...")
-			timer.Stop()
-			return
-		}
+// schedulerHolder identifies this replica in the scheduler lease
+func schedulerHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
 	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
 }
 
 func main() {
@@ -321,12 +419,21 @@ func main() {
 		log.Fatalf("invalid configuration: %v", err)
 	}
 
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		log.Fatalf("failed to load timezone %s: %v", cfg.Timezone, err)
+	}
+
 	storage, err := db.ConnectDB(cfg.DBPath)
 
 	if err != nil {
 		log.Fatalf("failed to create storage: %v", err)
 	}
 
+	if err := storage.SeedCompetitions(context.Background(), competitionsFromConfig(cfg)); err != nil {
+		log.Fatalf("failed to seed competitions: %v", err)
+	}
+
 	e := echo.New()
 	e.Use(middleware.Recover())
 
@@ -357,11 +464,48 @@ func main() {
 		log.Fatalf("Failed to set webhook: %v", err)
 	}
 
+	notifier := notification.NewTelegramNotifier(bot)
+
+	syncerCfg := syncer.Config{
+		APIBaseURL:      cfg.FootballAPI.BaseURL,
+		APIKey:          cfg.FootballAPI.APIKey,
+		WebAppURL:       cfg.WebAppURL,
+		OpenAIKey:       cfg.OpenAIKey,
+		ImagePreviewURL: cfg.OGImagePreviewSVC,
+		ChannelChatID:   cfg.TelegramChannelID,
+		BotWebApp:       cfg.BotWebApp,
+		Location:        location,
+		ReminderLead:    cfg.ReminderLead,
+	}
+
+	switch {
+	case cfg.FootballAPI.Provider == "file":
+		syncerCfg.Provider = football.NewFile(cfg.FootballAPI.DataDir)
+	case cfg.FootballAPI.Mode == "record":
+		log.Printf("Recording football API responses to %s", cfg.FootballAPI.FixturesDir)
+		syncerCfg.Provider = football.NewFootballData(cfg.FootballAPI.BaseURL, cfg.FootballAPI.APIKey,
+			football.WithTransport(&football.Recorder{Dir: cfg.FootballAPI.FixturesDir}))
+	case cfg.FootballAPI.Mode == "replay":
+		log.Printf("Replaying football API responses from %s", cfg.FootballAPI.FixturesDir)
+		syncerCfg.Provider = football.NewFootballData(cfg.FootballAPI.BaseURL, cfg.FootballAPI.APIKey,
+			football.WithTransport(&football.Replayer{Dir: cfg.FootballAPI.FixturesDir}),
+			football.WithLimiter(nil))
+	}
+
+	sync := syncer.NewSyncer(storage, notifier, syncerCfg)
+
+	sched := scheduler.New(storage, scheduler.Config{Holder: schedulerHolder()})
+
 	apiCfg := api.Config{
 		BotToken:  cfg.TelegramBotToken,
 		JWTSecret: cfg.JWTSecret,
 		AssetsURL: cfg.AssetsURL,
 		OpenAIKey: cfg.OpenAIKey,
+		Location:  location,
+
+		AdminUserIDs:  cfg.Admins,
+		Scheduler:     sched,
+		Notifications: sync,
 	}
 
 	s3Client, err := s3.NewS3Client(
@@ -392,15 +536,33 @@ func main() {
 
 	g.GET("/matches", a.ListMatches)
 	g.GET("/matches/:id", a.GetMatchByID)
+	g.GET("/matches/:id/odds", a.GetMatchOdds)
 	g.POST("/predictions", a.SavePrediction)
 	g.DELETE("/predictions/:id", a.CancelPrediction)
 	g.GET("/predictions", a.GetUserPredictions)
 	g.GET("/leaderboard", a.GetLeaderboard)
 	g.GET("/users/:username", a.GetUserInfo)
+	g.GET("/users/:username/rank-history", a.GetUserRankHistory)
+	g.POST("/users/:user_id/follow", a.FollowUserHandler)
+	g.DELETE("/users/:user_id/follow", a.UnfollowUserHandler)
+	g.GET("/users/:user_id/followers", a.GetFollowersHandler)
+	g.GET("/following", a.GetFollowingHandler)
+	g.GET("/search/users", a.SearchUsers)
+	g.GET("/follow-suggestions", a.GetFollowSuggestions)
+	g.GET("/feed", a.GetFeed)
+	g.GET("/matches/:id/comments", a.ListMatchComments)
+	g.POST("/matches/:id/comments", a.CreateMatchComment)
+	g.POST("/matches/:id/comments/:comment_id/reactions", a.AddCommentReaction)
+	g.DELETE("/matches/:id/comments/:comment_id/reactions/:emoji", a.RemoveCommentReaction)
+	g.POST("/matches/:id/comments/:comment_id/report", a.ReportComment)
 	g.GET("/seasons/active", a.GetActiveSeasons)
+	g.GET("/achievements", a.ListAchievements)
 	g.GET("/referrals", a.ListMyReferrals)
 	g.GET("/teams", a.ListTeams)
+	g.GET("/competitions/:code/standings", a.GetCompetitionStandings)
 	g.PUT("/users", a.UpdateUser)
+	g.GET("/users/notification-settings", a.GetNotificationSettings)
+	g.PUT("/users/notification-settings", a.UpdateNotificationSettings)
 	g.GET("/match/popular", a.GetTodayMostPopularMatch)
 	g.POST("/presigned-url", a.GetPresignedURL)
 	g.POST("/feedback", a.SaveSurvey)
@@ -409,31 +571,27 @@ func main() {
 	g.DELETE("/subscriptions", a.CancelSubscription)
 	//g.POST("/message", a.BroadcastSubscriptionMessage)
 
+	admin := g.Group("/admin", a.AdminOnly)
+	admin.GET("/competitions", a.ListCompetitions)
+	admin.PUT("/competitions/:code", a.SaveCompetition)
+	admin.GET("/teams/unresolved", a.ListUnresolvedTeams)
+	admin.POST("/teams/:id/aliases", a.AddTeamAlias)
+	admin.GET("/sync/status", a.GetSyncStatus)
+	admin.GET("/jobs", a.ListJobs)
+	admin.POST("/jobs/:name/run", a.RunJob)
+
 	done := make(chan bool, 1)
 
 	go gracefulShutdown(e, done)
 
-	notifier := notification.NewTelegramNotifier(bot)
-
-	syncerCfg := syncer.Config{
-		APIBaseURL:      cfg.FootballAPI.BaseURL,
-		APIKey:          cfg.FootballAPI.APIKey,
-		WebAppURL:       cfg.WebAppURL,
-		OpenAIKey:       cfg.OpenAIKey,
-		ImagePreviewURL: cfg.OGImagePreviewSVC,
-		ChannelChatID:   cfg.TelegramChannelID,
-		BotWebApp:       cfg.BotWebApp,
-	}
-
-	sync := syncer.NewSyncer(storage, notifier, syncerCfg)
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	go startSyncer(ctx, sync)
-
-	go startNotificationJob(ctx, sync)
+	if err := registerJobs(ctx, sched, sync, cfg, location); err != nil {
+		log.Fatalf("Failed to register jobs: %v", err)
+	}
 
-	// go startWeeklyRecapJob(ctx, sync)
+	go sched.Start(ctx)
 
 	if err := e.Start(fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)); err != nil {
 		log.Fatalf("failed to start server: %v", err)
@@ -0,0 +1,415 @@
+// Command simulate replays a recorded season against a fresh database on a virtual clock.
+// Background jobs run on their production schedules in simulated time while synthetic users
+// predict upcoming matches, so month rollovers, streaks, badges and weekly recaps can be
+// checked in seconds instead of waiting for them to happen.
+//
+//	go run ./cmd/simulate -fixtures internal/syncer/testdata/fixtures/fulltime -from 2024-08-15 -to 2024-08-20
+//
+// The fixtures directory holds football-data.org responses saved by the record mode of the
+// api command, or -provider file reads the file provider's layout.
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/project/internal/clock"
+	"github.com/user/project/internal/contract"
+	"github.com/user/project/internal/db"
+	"github.com/user/project/internal/football"
+	"github.com/user/project/internal/scheduler"
+	"github.com/user/project/internal/syncer"
+)
+
+type options struct {
+	dbPath        string
+	migrations    string
+	provider      string
+	fixtures      string
+	competitions  []string
+	from, to      time.Time
+	location      *time.Location
+	poll          time.Duration
+	users         int
+	seed          int64
+	speed         float64
+	verbose       bool
+	notifications bool
+}
+
+func parseFlags() (options, error) {
+	var (
+		o            options
+		competitions string
+		from, to     string
+		timezone     string
+	)
+
+	flag.StringVar(&o.dbPath, "db", "", "SQLite database to create, a temporary one by default")
+	flag.StringVar(&o.migrations, "migrations", "migrations", "Directory with the SQL migrations")
+	flag.StringVar(&o.provider, "provider", "replay", "Where fixtures come from: replay (recorded football-data.org responses) or file")
+	flag.StringVar(&o.fixtures, "fixtures", "fixtures", "Directory with the recorded season")
+	flag.StringVar(&competitions, "competitions", "PL", "Comma separated competition codes to simulate")
+	flag.StringVar(&from, "from", "", "First simulated day, YYYY-MM-DD; defaults to the day before the first kickoff")
+	flag.StringVar(&to, "to", "", "Last simulated day, YYYY-MM-DD; defaults to the day after the last kickoff")
+	flag.StringVar(&timezone, "timezone", "Europe/Moscow", "League timezone for seasons and daily jobs")
+	flag.DurationVar(&o.poll, "poll", 15*time.Minute, "Simulated interval between match syncs")
+	flag.IntVar(&o.users, "users", 50, "Number of synthetic users")
+	flag.Int64Var(&o.seed, "seed", 1, "Random seed for users and their predictions")
+	flag.Float64Var(&o.speed, "speed", 0, "Simulated seconds per real second, 0 runs as fast as possible")
+	flag.BoolVar(&o.verbose, "v", false, "Log what the jobs do")
+	flag.BoolVar(&o.notifications, "notifications", false, "Print every notification sent")
+	flag.Parse()
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return o, fmt.Errorf("failed to load timezone %s: %w", timezone, err)
+	}
+	o.location = loc
+
+	for _, code := range strings.Split(competitions, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			o.competitions = append(o.competitions, code)
+		}
+	}
+
+	for _, d := range []struct {
+		value string
+		dest  *time.Time
+	}{{from, &o.from}, {to, &o.to}} {
+		if d.value == "" {
+			continue
+		}
+		t, err := time.ParseInLocation("2006-01-02", d.value, loc)
+		if err != nil {
+			return o, fmt.Errorf("invalid date %q: %w", d.value, err)
+		}
+		*d.dest = t
+	}
+
+	if o.poll <= 0 {
+		return o, fmt.Errorf("poll interval must be positive")
+	}
+
+	return o, nil
+}
+
+// searchMigrations build user search, which needs the sqlite_fts5 build tag and plays no part in the simulation
+var searchMigrations = map[string]bool{
+	"6_user_search.sql":      true,
+	"20_user_search_key.sql": true,
+}
+
+// applyMigrations runs the numbered migrations in order on a fresh database
+func applyMigrations(conn *sql.DB, dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no migrations found in %s", dir)
+	}
+
+	number := func(path string) int {
+		n, _ := strconv.Atoi(strings.SplitN(filepath.Base(path), "_", 2)[0])
+		return n
+	}
+	sort.Slice(files, func(i, j int) bool { return number(files[i]) < number(files[j]) })
+
+	for _, file := range files {
+		if searchMigrations[filepath.Base(file)] {
+			log.Printf("Skipping %s", filepath.Base(file))
+			continue
+		}
+
+		query, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if _, err := conn.Exec(string(query)); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+	}
+
+	return nil
+}
+
+// notifier counts notifications per job instead of sending them
+type notifier struct {
+	job    string
+	counts map[string]int
+	print  bool
+	clock  clock.Clock
+}
+
+func (n *notifier) SendTextNotification(params contract.SendNotificationParams) error {
+	return n.record(params)
+}
+
+func (n *notifier) SendPhotoNotification(params contract.SendNotificationParams) error {
+	return n.record(params)
+}
+
+func (n *notifier) record(params contract.SendNotificationParams) error {
+	n.counts[n.job]++
+	if n.print {
+		fmt.Printf("%s  %-20s chat %d: %s\n", n.clock.Now().Format("2006-01-02 15:04"), n.job, params.ChatID, params.Message)
+	}
+	return nil
+}
+
+// imageServer stands in for the preview image service with a blank picture
+func imageServer() (*httptest.Server, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1, 1))); err != nil {
+		return nil, err
+	}
+	img := buf.Bytes()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(img)
+	})), nil
+}
+
+// job is a background job run at simulated times
+type job struct {
+	name     string
+	schedule scheduler.Schedule
+	run      func(ctx context.Context) error
+	next     time.Time
+	runs     int
+	failures int
+}
+
+func mustParse(spec string, loc *time.Location) scheduler.Schedule {
+	schedule, err := scheduler.Parse(spec, loc)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+func main() {
+	o, err := parseFlags()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := run(o); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(o options) error {
+	ctx := context.Background()
+
+	dbPath := o.dbPath
+	if dbPath == "" {
+		dir, err := os.MkdirTemp("", "simulate")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		dbPath = filepath.Join(dir, "simulate.db")
+	} else if _, err := os.Stat(dbPath); err == nil {
+		return fmt.Errorf("database %s already exists, the simulation needs a fresh one", dbPath)
+	}
+
+	conn, err := sql.Open("sql", dbPath)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := applyMigrations(conn, o.migrations); err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	var source syncer.FootballProvider
+	switch o.provider {
+	case "replay":
+		source = football.NewFootballData("https://api.football-data.org/v4", "",
+			football.WithTransport(&football.Replayer{Dir: o.fixtures}),
+			football.WithLimiter(nil))
+	case "file":
+		source = football.NewFile(o.fixtures)
+	default:
+		return fmt.Errorf("unknown provider %q", o.provider)
+	}
+
+	virtual := clock.NewManual(time.Now())
+	timeline := NewTimeline(source, virtual)
+
+	var fixtures []football.Match
+	for _, code := range o.competitions {
+		matches, err := timeline.Fixtures(ctx, code)
+		if err != nil {
+			return err
+		}
+		fixtures = append(fixtures, matches...)
+	}
+	if len(fixtures) == 0 {
+		return fmt.Errorf("no fixtures found in %s", o.fixtures)
+	}
+	sort.SliceStable(fixtures, func(i, j int) bool { return fixtures[i].UTCDate.Before(fixtures[j].UTCDate) })
+
+	from, to := o.from, o.to
+	if from.IsZero() {
+		from = clock.StartOfDay(fixtures[0].UTCDate, o.location).AddDate(0, 0, -1)
+	}
+	if to.IsZero() {
+		to = clock.StartOfDay(fixtures[len(fixtures)-1].UTCDate, o.location).AddDate(0, 0, 1)
+	}
+	to = to.AddDate(0, 0, 1) // The last day is simulated in full
+	virtual.Set(from)
+
+	images, err := imageServer()
+	if err != nil {
+		return err
+	}
+	defer images.Close()
+
+	if !o.verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	storage := db.NewStorage(conn)
+	storage.SetClock(virtual)
+
+	competitions := make([]db.Competition, len(o.competitions))
+	for i, code := range o.competitions {
+		competitions[i] = db.Competition{Code: code, Name: code, Enabled: true}
+	}
+	if err := storage.SeedCompetitions(ctx, competitions); err != nil {
+		return fmt.Errorf("failed to seed competitions: %w", err)
+	}
+
+	notify := &notifier{counts: make(map[string]int), print: o.notifications, clock: virtual}
+	sync := syncer.NewSyncer(storage, notify, syncer.Config{
+		Location:        o.location,
+		Clock:           virtual,
+		Provider:        timeline,
+		ImagePreviewURL: images.URL,
+	})
+
+	// The first reconcile brings in teams and fixtures, users need both
+	if err := sync.ManageSeasons(ctx); err != nil {
+		return fmt.Errorf("failed to create the first season: %w", err)
+	}
+	if err := sync.SyncTeams(ctx); err != nil {
+		return fmt.Errorf("failed to sync teams: %w", err)
+	}
+	if err := sync.ReconcileMatches(ctx); err != nil {
+		return fmt.Errorf("failed to sync matches: %w", err)
+	}
+
+	crowd := NewCrowd(storage, o.seed)
+	if err := crowd.Seed(ctx, o.users); err != nil {
+		return err
+	}
+
+	seasons := newSeasonLog(storage)
+	predictions := 0
+
+	// Production schedules, except the weekly recap which is off by default there
+	jobs := []*job{
+		{
+			name:     "match_poll",
+			schedule: scheduler.Every(o.poll),
+			run: func(ctx context.Context) error {
+				if err := sync.SyncMatches(ctx); err != nil {
+					return err
+				}
+				if err := sync.ProcessPredictions(ctx); err != nil {
+					return err
+				}
+				if err := sync.ManageSeasons(ctx); err != nil {
+					return err
+				}
+				return seasons.observe(ctx)
+			},
+		},
+		{
+			name:     "predictions",
+			schedule: scheduler.Every(time.Hour),
+			run: func(ctx context.Context) error {
+				saved, err := crowd.Predict(ctx, virtual.Now(), fixtures)
+				predictions += saved
+				return err
+			},
+		},
+		{
+			name:     "match_reconcile",
+			schedule: mustParse("@every 6h", o.location),
+			run: func(ctx context.Context) error {
+				if err := sync.ReconcileMatches(ctx); err != nil {
+					return err
+				}
+				return sync.SyncStandings(ctx)
+			},
+		},
+		{name: "popularity", schedule: mustParse("@every 30m", o.location), run: sync.RefreshPopularity},
+		{name: "notifications", schedule: mustParse("0 10 * * *", o.location), run: sync.SendMatchNotification},
+		{name: "kickoff_reminders", schedule: mustParse("@every 5m", o.location), run: sync.SendKickoffReminders},
+		{name: "queued_notifications", schedule: mustParse("@every 5m", o.location), run: sync.SendQueuedNotifications},
+		{name: "leaderboard_snapshot", schedule: mustParse("5 0 * * *", o.location), run: sync.SnapshotLeaderboards},
+		{name: "weekly_recap", schedule: mustParse("0 10 * * 1", o.location), run: sync.SendWeeklyRecap},
+	}
+
+	for _, j := range jobs {
+		j.next = j.schedule.Next(from)
+	}
+	if err := seasons.observe(ctx); err != nil {
+		return err
+	}
+
+	fmt.Printf("Simulating %s from %s to %s with %d users\n",
+		strings.Join(o.competitions, ", "), from.Format("2006-01-02"), to.AddDate(0, 0, -1).Format("2006-01-02"), o.users)
+	started := time.Now()
+
+	for {
+		// Jobs due at the same time run in the order listed
+		var due *job
+		for _, j := range jobs {
+			if due == nil || j.next.Before(due.next) {
+				due = j
+			}
+		}
+		if !due.next.Before(to) {
+			break
+		}
+
+		if o.speed > 0 {
+			time.Sleep(time.Duration(float64(due.next.Sub(virtual.Now())) / o.speed))
+		}
+		virtual.Set(due.next)
+
+		notify.job = due.name
+		due.runs++
+		if err := due.run(ctx); err != nil {
+			due.failures++
+			fmt.Printf("%s  %s failed: %v\n", due.next.In(o.location).Format("2006-01-02 15:04"), due.name, err)
+		}
+		due.next = due.schedule.Next(due.next)
+	}
+
+	fmt.Printf("Done in %s\n\n", time.Since(started).Round(time.Millisecond))
+	return report(ctx, storage, crowd, seasons, jobs, notify, predictions, o.location)
+}
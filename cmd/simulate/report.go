@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/user/project/internal/db"
+)
+
+type reportStorage interface {
+	GetActiveSeason(ctx context.Context, seasonType string) (db.Season, error)
+	GetLeaderboard(ctx context.Context, seasonID string) ([]db.LeaderboardEntry, error)
+	GetAllUsers(ctx context.Context) ([]db.User, error)
+}
+
+// seasonLog remembers every monthly season the simulation went through
+type seasonLog struct {
+	storage reportStorage
+	seasons []db.Season
+}
+
+func newSeasonLog(storage reportStorage) *seasonLog {
+	return &seasonLog{storage: storage}
+}
+
+func (l *seasonLog) observe(ctx context.Context) error {
+	season, err := l.storage.GetActiveSeason(ctx, db.SeasonTypeMonthly)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get active season: %w", err)
+	}
+
+	if n := len(l.seasons); n == 0 || l.seasons[n-1].ID != season.ID {
+		l.seasons = append(l.seasons, season)
+	}
+	return nil
+}
+
+func report(ctx context.Context, storage reportStorage, crowd *Crowd, seasons *seasonLog, jobs []*job, notify *notifier, predictions int, loc *time.Location) error {
+	fmt.Println("Jobs")
+	for _, j := range jobs {
+		fmt.Printf("  %-22s %6d runs %4d failed %6d notifications\n", j.name, j.runs, j.failures, notify.counts[j.name])
+	}
+
+	fmt.Println("\nMonthly seasons")
+	for _, season := range seasons.seasons {
+		fmt.Printf("  %-4s %s – %s\n", season.Name,
+			season.StartDate.In(loc).Format("2006-01-02"), season.EndDate.In(loc).Format("2006-01-02"))
+
+		leaderboard, err := storage.GetLeaderboard(ctx, season.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get leaderboard of season %s: %w", season.Name, err)
+		}
+		for _, entry := range leaderboard[:min(3, len(leaderboard))] {
+			fmt.Printf("       %d. %-10s %4d points\n", entry.Position, crowd.Username(entry.UserID), entry.Points)
+		}
+	}
+
+	users, err := storage.GetAllUsers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get users: %w", err)
+	}
+
+	settled, correct := 0, 0
+	for _, u := range users {
+		settled += u.TotalPredictions
+		correct += u.CorrectPredictions
+	}
+	fmt.Printf("\nPredictions: %d made, %d settled, %d correct\n", predictions, settled, correct)
+
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i].LongestWinStreak > users[j].LongestWinStreak
+	})
+	fmt.Println("\nLongest win streaks")
+	for _, u := range users[:min(5, len(users))] {
+		fmt.Printf("  %-10s longest %2d, current %2d\n", u.Username, u.LongestWinStreak, u.CurrentWinStreak)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/project/internal/clock"
+	"github.com/user/project/internal/football"
+	"github.com/user/project/internal/syncer"
+)
+
+// matchLength is how long a match stays live after kickoff before its result shows up
+const matchLength = 2 * time.Hour
+
+// Timeline serves a season recorded after the fact as it looked at the clock's time:
+// matches are scheduled until kickoff, live for matchLength and only then carry their score.
+// Standings are built from the results known so far.
+type Timeline struct {
+	source syncer.FootballProvider
+	clock  clock.Clock
+
+	mu       sync.Mutex
+	fixtures map[string][]football.Match
+}
+
+func NewTimeline(source syncer.FootballProvider, c clock.Clock) *Timeline {
+	return &Timeline{
+		source:   source,
+		clock:    c,
+		fixtures: make(map[string][]football.Match),
+	}
+}
+
+func (t *Timeline) GetTeams(ctx context.Context, competition string) ([]football.Team, error) {
+	return t.source.GetTeams(ctx, competition)
+}
+
+func (t *Timeline) GetMatches(ctx context.Context, competition string, filter football.MatchFilter) ([]football.Match, error) {
+	fixtures, err := t.Fixtures(ctx, competition)
+	if err != nil {
+		return nil, err
+	}
+
+	now := t.clock.Now()
+	matches := make([]football.Match, 0, len(fixtures))
+	for _, m := range fixtures {
+		if filter.Contains(m.UTCDate) {
+			matches = append(matches, at(m, now))
+		}
+	}
+
+	return matches, nil
+}
+
+func (t *Timeline) GetStandings(ctx context.Context, competition string) ([]football.Standing, error) {
+	fixtures, err := t.Fixtures(ctx, competition)
+	if err != nil {
+		return nil, err
+	}
+
+	return standings(fixtures, t.clock.Now()), nil
+}
+
+// Fixtures returns the competition's whole season as recorded, results included
+func (t *Timeline) Fixtures(ctx context.Context, competition string) ([]football.Match, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if fixtures, ok := t.fixtures[competition]; ok {
+		return fixtures, nil
+	}
+
+	fixtures, err := t.source.GetMatches(ctx, competition, football.MatchFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fixtures for %s: %w", competition, err)
+	}
+
+	sort.SliceStable(fixtures, func(i, j int) bool {
+		return fixtures[i].UTCDate.Before(fixtures[j].UTCDate)
+	})
+
+	t.fixtures[competition] = fixtures
+	return fixtures, nil
+}
+
+// at masks what wasn't known about the match at now. LastUpdated is set to the
+// moment of the last transition, so incremental sync cursors behave as they would live.
+func at(m football.Match, now time.Time) football.Match {
+	if m.Status == football.StatusPostponed || m.Status == football.StatusCancelled {
+		return m
+	}
+
+	end := m.UTCDate.Add(matchLength)
+	switch {
+	case now.Before(m.UTCDate):
+		m.Status = football.StatusScheduled
+		m.Score = football.Score{}
+		m.LastUpdated = time.Time{}
+	case now.Before(end):
+		m.Status = football.StatusLive
+		m.Score = football.Score{}
+		m.LastUpdated = m.UTCDate
+	default:
+		// Recordings made mid-season still list later matches as scheduled
+		if m.Status == football.StatusFinished {
+			m.LastUpdated = end
+		}
+	}
+
+	return m
+}
+
+// standings builds a single league table from the matches finished by now
+func standings(fixtures []football.Match, now time.Time) []football.Standing {
+	rows := make(map[string]*football.Standing)
+	var order []string
+	forms := make(map[string][]string)
+
+	row := func(team football.MatchTeam) *football.Standing {
+		r, ok := rows[team.ID]
+		if !ok {
+			team.LeagueRank = nil
+			r = &football.Standing{Team: team}
+			rows[team.ID] = r
+			order = append(order, team.ID)
+		}
+		return r
+	}
+
+	for _, fixture := range fixtures {
+		home, away := row(fixture.HomeTeam), row(fixture.AwayTeam)
+
+		m := at(fixture, now)
+		if m.Status != football.StatusFinished || m.Score.Home == nil || m.Score.Away == nil {
+			continue
+		}
+
+		homeGoals, awayGoals := *m.Score.Home, *m.Score.Away
+		for _, side := range []struct {
+			row                    *football.Standing
+			goalsFor, goalsAgainst int
+		}{{home, homeGoals, awayGoals}, {away, awayGoals, homeGoals}} {
+			r := side.row
+			r.PlayedGames++
+			r.GoalsFor += side.goalsFor
+			r.GoalsAgainst += side.goalsAgainst
+			r.GoalDifference = r.GoalsFor - r.GoalsAgainst
+
+			result := "D"
+			switch {
+			case side.goalsFor > side.goalsAgainst:
+				r.Won++
+				r.Points += 3
+				result = "W"
+			case side.goalsFor < side.goalsAgainst:
+				r.Lost++
+				result = "L"
+			default:
+				r.Draw++
+				r.Points++
+			}
+			forms[r.Team.ID] = append([]string{result}, forms[r.Team.ID]...)
+		}
+	}
+
+	table := make([]football.Standing, 0, len(order))
+	for _, id := range order {
+		r := *rows[id]
+		form := forms[id]
+		if len(form) > 5 {
+			form = form[:5]
+		}
+		r.Form = strings.Join(form, ",")
+		table = append(table, r)
+	}
+
+	sort.SliceStable(table, func(i, j int) bool {
+		a, b := table[i], table[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.GoalDifference != b.GoalDifference {
+			return a.GoalDifference > b.GoalDifference
+		}
+		if a.GoalsFor != b.GoalsFor {
+			return a.GoalsFor > b.GoalsFor
+		}
+		return a.Team.Name < b.Team.Name
+	})
+
+	for i := range table {
+		table[i].Position = i + 1
+	}
+
+	return table
+}
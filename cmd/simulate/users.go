@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/user/project/internal/db"
+	"github.com/user/project/internal/football"
+)
+
+// predictionLead is how long before kickoff synthetic users make up their mind about a match
+const predictionLead = 24 * time.Hour
+
+type simStorage interface {
+	CreateUser(user db.User) error
+	UpdateUserInformation(ctx context.Context, user db.User) error
+	ListTeams(ctx context.Context) ([]db.Team, error)
+	GetMatchByID(ctx context.Context, matchID string) (db.Match, error)
+	SavePrediction(ctx context.Context, prediction db.Prediction) error
+}
+
+// simUser is a synthetic user. Activity is the chance they predict a match at all,
+// skill the chance they call the outcome right; fans always predict their team's matches.
+type simUser struct {
+	db.User
+	activity float64
+	skill    float64
+	scores   bool // Predicts exact scores rather than outcomes
+}
+
+// Crowd generates users and has them predict upcoming matches
+type Crowd struct {
+	storage simStorage
+	rnd     *rand.Rand
+	users   []simUser
+	decided map[string]bool // user and match IDs already considered
+}
+
+func NewCrowd(storage simStorage, seed int64) *Crowd {
+	return &Crowd{
+		storage: storage,
+		rnd:     rand.New(rand.NewSource(seed)),
+		decided: make(map[string]bool),
+	}
+}
+
+// Seed creates n users, about a third of them supporting a team from the synced ones
+func (c *Crowd) Seed(ctx context.Context, n int) error {
+	teams, err := c.storage.ListTeams(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list teams: %w", err)
+	}
+
+	for i := 0; i < n; i++ {
+		lang := "en"
+		if i%2 == 1 {
+			lang = "ru"
+		}
+		name := fmt.Sprintf("Sim %d", i+1)
+
+		user := simUser{
+			User: db.User{
+				ID:           fmt.Sprintf("sim-%04d", i+1),
+				FirstName:    &name,
+				Username:     fmt.Sprintf("sim_%04d", i+1),
+				LanguageCode: &lang,
+				ChatID:       int64(1_000_000 + i),
+				Privacy:      db.PrivacyPublic,
+			},
+			activity: 0.2 + 0.8*c.rnd.Float64(),
+			skill:    0.3 + 0.4*c.rnd.Float64(),
+			scores:   c.rnd.Intn(2) == 0,
+		}
+
+		if err := c.storage.CreateUser(user.User); err != nil {
+			return fmt.Errorf("failed to create user %s: %w", user.ID, err)
+		}
+
+		if len(teams) > 0 && c.rnd.Intn(3) == 0 {
+			team := teams[c.rnd.Intn(len(teams))]
+			user.FavoriteTeamID = &team.ID
+			if err := c.storage.UpdateUserInformation(ctx, user.User); err != nil {
+				return fmt.Errorf("failed to set favorite team for user %s: %w", user.ID, err)
+			}
+		}
+
+		c.users = append(c.users, user)
+	}
+
+	return nil
+}
+
+// Username returns the synthetic user's name for reports
+func (c *Crowd) Username(id string) string {
+	for _, u := range c.users {
+		if u.ID == id {
+			return u.Username
+		}
+	}
+	return id
+}
+
+// Predict lets every user decide on the matches kicking off within predictionLead of now.
+// Knowing the recorded result, a skilled guess is right by construction.
+func (c *Crowd) Predict(ctx context.Context, now time.Time, fixtures []football.Match) (int, error) {
+	saved := 0
+	for _, m := range fixtures {
+		if !m.UTCDate.After(now) || m.UTCDate.After(now.Add(predictionLead)) {
+			continue
+		}
+
+		if _, err := c.storage.GetMatchByID(ctx, m.ID); err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				continue // Not synced yet
+			}
+			return saved, fmt.Errorf("failed to get match %s: %w", m.ID, err)
+		}
+
+		for _, u := range c.users {
+			key := u.ID + "/" + m.ID
+			if c.decided[key] {
+				continue
+			}
+			c.decided[key] = true
+
+			fan := u.FavoriteTeamID != nil && (*u.FavoriteTeamID == m.HomeTeam.ID || *u.FavoriteTeamID == m.AwayTeam.ID)
+			if !fan && c.rnd.Float64() >= u.activity {
+				continue
+			}
+
+			if err := c.storage.SavePrediction(ctx, c.guess(u, m)); err != nil {
+				return saved, fmt.Errorf("failed to save prediction of %s for match %s: %w", u.ID, m.ID, err)
+			}
+			saved++
+		}
+	}
+
+	return saved, nil
+}
+
+func (c *Crowd) guess(u simUser, m football.Match) db.Prediction {
+	home, away := c.rnd.Intn(4), c.rnd.Intn(3)
+	if m.Score.Home != nil && m.Score.Away != nil && c.rnd.Float64() < u.skill {
+		home, away = *m.Score.Home, *m.Score.Away
+		// Calling the outcome right doesn't mean hitting the exact score
+		if c.rnd.Intn(3) > 0 {
+			home, away = home+1, away+1
+		}
+	}
+
+	prediction := db.Prediction{UserID: u.ID, MatchID: m.ID}
+	if u.scores {
+		prediction.PredictedHomeScore = &home
+		prediction.PredictedAwayScore = &away
+		return prediction
+	}
+
+	outcome := db.MatchOutcomeDraw
+	switch {
+	case home > away:
+		outcome = db.MatchOutcomeHome
+	case home < away:
+		outcome = db.MatchOutcomeAway
+	}
+	prediction.PredictedOutcome = &outcome
+	return prediction
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"github.com/labstack/echo/v4"
+	"github.com/user/project/internal/contract"
+	"github.com/user/project/internal/db"
+	"github.com/user/project/internal/terrors"
+	"net/http"
+	"time"
+)
+
+// ListAchievements returns every badge with the caller's progress toward it.
+// Progress is derived from predictions, streaks and the leaderboard on each request.
+func (a *API) ListAchievements(c echo.Context) error {
+	ctx := c.Request().Context()
+	uid := GetContextUserID(c)
+
+	user, err := a.storage.GetUserByID(uid)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get user")
+	}
+
+	badges, err := a.storage.ListBadges(ctx)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to list badges")
+	}
+
+	awarded := make(map[string]time.Time, len(user.Badges))
+	for _, badge := range user.Badges {
+		awarded[badge.ID] = badge.AwardedAt
+	}
+
+	resp := make([]contract.AchievementResponse, 0, len(badges))
+	for _, badge := range badges {
+		achievement, err := a.achievementProgress(ctx, user, badge)
+		if err != nil {
+			return terrors.InternalServer(err, "failed to compute achievement progress")
+		}
+
+		if awardedAt, ok := awarded[badge.ID]; ok {
+			achievement.Earned = true
+			achievement.AwardedAt = &awardedAt
+			achievement.Current = achievement.Target
+		}
+
+		resp = append(resp, achievement)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (a *API) achievementProgress(ctx context.Context, user db.User, badge db.Badge) (contract.AchievementResponse, error) {
+	res := contract.AchievementResponse{Badge: badge}
+
+	if length, ok := db.BadgeWinStreakLengths[badge.ID]; ok {
+		res.Current = min(user.CurrentWinStreak, length)
+		res.Target = length
+		res.Unit = "streak"
+		return res, nil
+	}
+
+	switch badge.ID {
+	case db.BadgeFirstExactScore:
+		count, err := a.storage.CountExactScorePredictions(ctx, user.ID)
+		if err != nil {
+			return res, err
+		}
+		res.Current = min(count, 1)
+		res.Target = 1
+		res.Unit = "predictions"
+	case db.BadgePredictions100:
+		res.Current = min(user.TotalPredictions, db.BadgePredictionsTarget)
+		res.Target = db.BadgePredictionsTarget
+		res.Unit = "predictions"
+	case db.BadgeSeasonTop10:
+		// Current is the best position in an active season, 0 when unranked
+		ranks, err := a.storage.GetUserRank(ctx, user.ID)
+		if err != nil {
+			return res, err
+		}
+		for _, rank := range ranks {
+			if res.Current == 0 || rank.Position < res.Current {
+				res.Current = rank.Position
+			}
+		}
+		res.Target = db.BadgeSeasonTopPosition
+		res.Unit = "position"
+	case db.BadgeFullMatchday:
+		current, target, err := a.matchdayProgress(ctx, user.ID)
+		if err != nil {
+			return res, err
+		}
+		res.Current = current
+		res.Target = target
+		res.Unit = "matches"
+	}
+
+	return res, nil
+}
+
+// matchdayProgress finds the upcoming matchday the user is closest to predicting in full.
+// A matchday is a round of a competition season, the same grouping the badge rule uses, counted in full
+// even when some of its matches are already played or more than a week away.
+func (a *API) matchdayProgress(ctx context.Context, userID string) (int, int, error) {
+	matches, err := a.storage.GetActiveMatches(ctx, userID, a.now())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	type matchday struct {
+		competition string
+		season      string
+		number      int
+	}
+
+	seen := make(map[matchday]bool)
+	bestCurrent, bestTarget := 0, 0
+	for _, match := range matches {
+		if match.CompetitionCode == "" || match.Matchday == nil {
+			continue
+		}
+
+		key := matchday{match.CompetitionCode, match.CompetitionSeason, *match.Matchday}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		total, predicted, err := a.storage.CountMatchdayPredictions(ctx, userID, key.competition, key.season, key.number)
+		if err != nil {
+			return 0, 0, err
+		}
+		if total < db.MinMatchdayMatches {
+			continue
+		}
+
+		// keep the matchday with the highest share of predicted matches
+		if bestTarget == 0 || predicted*bestTarget > bestCurrent*total {
+			bestCurrent, bestTarget = predicted, total
+		}
+	}
+
+	if bestTarget == 0 {
+		bestTarget = db.MinMatchdayMatches
+	}
+
+	return bestCurrent, bestTarget, nil
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/user/project/internal/contract"
+	"github.com/user/project/internal/db"
+	"github.com/user/project/internal/scheduler"
+	"github.com/user/project/internal/terrors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// AdminOnly lets through users listed in Config.AdminUserIDs
+func (a *API) AdminOnly(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		uid := GetContextUserID(c)
+		for _, id := range a.cfg.AdminUserIDs {
+			if uid != "" && id == uid {
+				return next(c)
+			}
+		}
+
+		return terrors.Forbidden(errors.New("not an admin"), "admin access required")
+	}
+}
+
+func (a *API) ListCompetitions(c echo.Context) error {
+	competitions, err := a.storage.ListCompetitions(c.Request().Context())
+	if err != nil {
+		return terrors.InternalServer(err, "failed to list competitions")
+	}
+
+	return c.JSON(http.StatusOK, competitions)
+}
+
+// SaveCompetition creates or updates the competition with the code from the path
+func (a *API) SaveCompetition(c echo.Context) error {
+	var req contract.CompetitionRequest
+	if err := c.Bind(&req); err != nil {
+		return terrors.BadRequest(err, "failed to decode request")
+	}
+	if err := req.Validate(); err != nil {
+		return terrors.BadRequest(err, "failed to validate request")
+	}
+
+	ctx := c.Request().Context()
+	code := strings.ToUpper(c.Param("code"))
+
+	competition := db.Competition{
+		Code:         code,
+		Name:         req.Name,
+		Emblem:       req.Emblem,
+		Area:         req.Area,
+		Enabled:      true,
+		SyncPriority: req.SyncPriority,
+	}
+	if req.Enabled != nil {
+		competition.Enabled = *req.Enabled
+	}
+
+	if err := a.storage.SaveCompetition(ctx, competition); err != nil {
+		return terrors.InternalServer(err, "failed to save competition")
+	}
+
+	saved, err := a.storage.GetCompetitionByCode(ctx, code)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get competition")
+	}
+
+	return c.JSON(http.StatusOK, saved)
+}
+
+// ListUnresolvedTeams reports team names from matches that couldn't be mapped to a team
+func (a *API) ListUnresolvedTeams(c echo.Context) error {
+	teams, err := a.storage.ListUnresolvedTeams(c.Request().Context())
+	if err != nil {
+		return terrors.InternalServer(err, "failed to list unresolved teams")
+	}
+
+	return c.JSON(http.StatusOK, teams)
+}
+
+// AddTeamAlias maps another name to an existing team so matches using it get resolved
+func (a *API) AddTeamAlias(c echo.Context) error {
+	var req contract.TeamAliasRequest
+	if err := c.Bind(&req); err != nil {
+		return terrors.BadRequest(err, "failed to decode request")
+	}
+	if err := req.Validate(); err != nil {
+		return terrors.BadRequest(err, "failed to validate request")
+	}
+
+	ctx := c.Request().Context()
+
+	team, err := a.storage.GetTeamByID(ctx, c.Param("id"))
+	if err != nil && errors.Is(err, db.ErrNotFound) {
+		return terrors.NotFound(err, "team not found")
+	} else if err != nil {
+		return terrors.InternalServer(err, "failed to get team")
+	}
+
+	if err := a.storage.AddTeamAlias(ctx, team.ID, strings.TrimSpace(req.Alias)); err != nil {
+		return terrors.InternalServer(err, "failed to add team alias")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetSyncStatus shows recent background job runs and when each competition last synced successfully
+func (a *API) GetSyncStatus(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	limit := 50
+	if val := c.QueryParam("limit"); val != "" {
+		var err error
+		limit, err = strconv.Atoi(val)
+		if err != nil || limit <= 0 || limit > 500 {
+			return terrors.BadRequest(err, "limit must be a number between 1 and 500")
+		}
+	}
+
+	runs, err := a.storage.ListJobRuns(ctx, c.QueryParam("job"), limit)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to list job runs")
+	}
+
+	competitions, err := a.storage.ListCompetitions(ctx)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to list competitions")
+	}
+
+	return c.JSON(http.StatusOK, contract.SyncStatusResponse{
+		Runs:         runs,
+		Competitions: competitions,
+	})
+}
+
+// ListJobs shows scheduled background jobs as seen by this instance
+func (a *API) ListJobs(c echo.Context) error {
+	if a.cfg.Scheduler == nil {
+		return terrors.NotFound(errors.New("scheduler not configured"), "scheduler is not running")
+	}
+
+	return c.JSON(http.StatusOK, contract.JobsResponse{
+		Leader: a.cfg.Scheduler.IsLeader(),
+		Jobs:   a.cfg.Scheduler.Jobs(),
+	})
+}
+
+// RunJob starts a background job right away, outside of its schedule
+func (a *API) RunJob(c echo.Context) error {
+	if a.cfg.Scheduler == nil {
+		return terrors.NotFound(errors.New("scheduler not configured"), "scheduler is not running")
+	}
+
+	err := a.cfg.Scheduler.RunNow(c.Param("name"))
+	switch {
+	case errors.Is(err, scheduler.ErrUnknownJob):
+		return terrors.NotFound(err, "job not found")
+	case errors.Is(err, scheduler.ErrAlreadyRunning):
+		return terrors.Conflict(err, "job is already running")
+	case errors.Is(err, scheduler.ErrNotLeader), errors.Is(err, scheduler.ErrNotStarted):
+		return terrors.Conflict(err, "jobs run on another instance")
+	case errors.Is(err, scheduler.ErrStopped):
+		return terrors.Conflict(err, "scheduler is shutting down")
+	case err != nil:
+		return terrors.InternalServer(err, "failed to run job")
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
@@ -136,6 +136,7 @@ func (a *API) TelegramAuth(c echo.Context) error {
 		Badges:             user.Badges,
 		SubscriptionActive: user.SubscriptionActive,
 		SubscriptionExpiry: user.SubscriptionExpiry,
+		Privacy:            user.Privacy,
 	}
 
 	if uresp.TotalPredictions > 0 {
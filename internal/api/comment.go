@@ -0,0 +1,249 @@
+package api
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/user/project/internal/contract"
+	"github.com/user/project/internal/db"
+	"github.com/user/project/internal/nanoid"
+	"github.com/user/project/internal/terrors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultCommentsLimit = 30
+	maxCommentsLimit     = 100
+
+	// commentRateLimit is how many comments a user can post within commentRateWindow
+	commentRateLimit  = 5
+	commentRateWindow = time.Minute
+)
+
+// ListMatchComments returns the match thread newest first.
+// Clients poll it with If-None-Match and get 304 while nothing has changed.
+func (a *API) ListMatchComments(c echo.Context) error {
+	ctx := c.Request().Context()
+	uid := GetContextUserID(c)
+	matchID := c.Param("id")
+
+	cursorParam := c.QueryParam("cursor")
+	cursor, err := decodeCursor(cursorParam)
+	if err != nil {
+		return terrors.BadRequest(err, "invalid cursor")
+	}
+
+	limit := defaultCommentsLimit
+	if val := c.QueryParam("limit"); val != "" {
+		limit, err = strconv.Atoi(val)
+		if err != nil || limit <= 0 || limit > maxCommentsLimit {
+			return terrors.BadRequest(err, fmt.Sprintf("limit must be between 1 and %d", maxCommentsLimit))
+		}
+	}
+
+	match, err := a.storage.GetMatchByID(ctx, matchID)
+	if err != nil && errors.Is(err, db.ErrNotFound) {
+		return terrors.NotFound(err, "match not found")
+	} else if err != nil {
+		return terrors.InternalServer(err, "failed to get match")
+	}
+
+	started := matchStarted(match, a.now())
+
+	version, err := a.storage.GetMatchCommentsVersion(ctx, matchID)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get comments version")
+	}
+
+	// Spoilers are revealed at kickoff and reactions are per viewer, so both go into the tag
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%s|%s|%d|%t", version, uid, cursorParam, limit, started)))
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	c.Response().Header().Set("ETag", etag)
+	if c.Request().Header.Get("If-None-Match") == etag {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	comments, err := a.storage.ListMatchComments(ctx, matchID, uid, cursor, limit)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get comments")
+	}
+
+	resp := contract.CommentsResponse{Items: make([]contract.CommentResponse, 0, len(comments))}
+	for _, comment := range comments {
+		item := toCommentResponse(comment)
+		if comment.IsSpoiler && !started && comment.UserID != uid {
+			item.Body = ""
+			item.Hidden = true
+		}
+		resp.Items = append(resp.Items, item)
+	}
+
+	if len(comments) == limit {
+		last := comments[len(comments)-1]
+		next := encodeCursor(db.Cursor{Time: last.CreatedAt, ID: last.ID})
+		resp.NextCursor = &next
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (a *API) CreateMatchComment(c echo.Context) error {
+	var req contract.CommentRequest
+	if err := c.Bind(&req); err != nil {
+		return terrors.BadRequest(err, "failed to decode request")
+	}
+	if err := req.Validate(); err != nil {
+		return terrors.BadRequest(err, "failed to validate request")
+	}
+
+	ctx := c.Request().Context()
+	uid := GetContextUserID(c)
+	matchID := c.Param("id")
+
+	if _, err := a.storage.GetMatchByID(ctx, matchID); err != nil && errors.Is(err, db.ErrNotFound) {
+		return terrors.NotFound(err, "match not found")
+	} else if err != nil {
+		return terrors.InternalServer(err, "failed to get match")
+	}
+
+	count, err := a.storage.CountUserCommentsSince(ctx, uid, a.now().Add(-commentRateWindow))
+	if err != nil {
+		return terrors.InternalServer(err, "failed to check comment rate")
+	}
+	if count >= commentRateLimit {
+		return terrors.TooManyRequests(errors.New("comment rate limit exceeded"), "you are commenting too fast, try again in a minute")
+	}
+
+	user, err := a.storage.GetUserByID(uid)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get user")
+	}
+
+	comment := db.Comment{
+		ID:        nanoid.Must(),
+		MatchID:   matchID,
+		UserID:    uid,
+		Body:      req.Body,
+		IsSpoiler: req.IsSpoiler,
+		CreatedAt: a.now().UTC(),
+		Author:    user,
+		Reactions: []db.ReactionCount{},
+	}
+
+	if err := a.storage.SaveComment(ctx, comment); err != nil {
+		return terrors.InternalServer(err, "failed to save comment")
+	}
+
+	return c.JSON(http.StatusCreated, toCommentResponse(comment))
+}
+
+func (a *API) AddCommentReaction(c echo.Context) error {
+	var req contract.ReactionRequest
+	if err := c.Bind(&req); err != nil {
+		return terrors.BadRequest(err, "failed to decode request")
+	}
+	if err := req.Validate(); err != nil {
+		return terrors.BadRequest(err, "failed to validate request")
+	}
+
+	ctx := c.Request().Context()
+	uid := GetContextUserID(c)
+
+	comment, err := a.getMatchComment(c)
+	if err != nil {
+		return err
+	}
+
+	if err := a.storage.AddCommentReaction(ctx, comment.ID, uid, req.Emoji); err != nil {
+		return terrors.InternalServer(err, "failed to add reaction")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (a *API) RemoveCommentReaction(c echo.Context) error {
+	ctx := c.Request().Context()
+	uid := GetContextUserID(c)
+
+	comment, err := a.getMatchComment(c)
+	if err != nil {
+		return err
+	}
+
+	if err := a.storage.RemoveCommentReaction(ctx, comment.ID, uid, c.Param("emoji")); err != nil {
+		return terrors.InternalServer(err, "failed to remove reaction")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (a *API) ReportComment(c echo.Context) error {
+	var req contract.ReportCommentRequest
+	if err := c.Bind(&req); err != nil {
+		return terrors.BadRequest(err, "failed to decode request")
+	}
+	if err := req.Validate(); err != nil {
+		return terrors.BadRequest(err, "failed to validate request")
+	}
+
+	ctx := c.Request().Context()
+	uid := GetContextUserID(c)
+
+	comment, err := a.getMatchComment(c)
+	if err != nil {
+		return err
+	}
+
+	if comment.UserID == uid {
+		return terrors.BadRequest(errors.New("cannot report own comment"), "you cannot report your own comment")
+	}
+
+	err = a.storage.ReportComment(ctx, comment.ID, uid, req.Reason)
+	if err != nil && errors.Is(err, db.ErrAlreadyExists) {
+		return terrors.Conflict(err, "comment already reported")
+	} else if err != nil {
+		return terrors.InternalServer(err, "failed to report comment")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// getMatchComment loads the comment from the path and checks it belongs to the match in the path
+func (a *API) getMatchComment(c echo.Context) (db.Comment, error) {
+	comment, err := a.storage.GetCommentByID(c.Request().Context(), c.Param("comment_id"))
+	if err != nil && errors.Is(err, db.ErrNotFound) {
+		return db.Comment{}, terrors.NotFound(err, "comment not found")
+	} else if err != nil {
+		return db.Comment{}, terrors.InternalServer(err, "failed to get comment")
+	}
+
+	if comment.MatchID != c.Param("id") {
+		return db.Comment{}, terrors.NotFound(errors.New("comment belongs to another match"), "comment not found")
+	}
+
+	return comment, nil
+}
+
+func toCommentResponse(comment db.Comment) contract.CommentResponse {
+	return contract.CommentResponse{
+		ID:      comment.ID,
+		MatchID: comment.MatchID,
+		User: contract.UserProfile{
+			ID:           comment.Author.ID,
+			FirstName:    comment.Author.FirstName,
+			LastName:     comment.Author.LastName,
+			Username:     comment.Author.Username,
+			AvatarURL:    comment.Author.AvatarURL,
+			FavoriteTeam: comment.Author.FavoriteTeam,
+		},
+		Body:      comment.Body,
+		IsSpoiler: comment.IsSpoiler,
+		CreatedAt: comment.CreatedAt,
+		Reactions: comment.Reactions,
+	}
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/user/project/internal/db"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// encodeCursor makes an opaque pagination token out of the last item of a page
+func encodeCursor(cursor db.Cursor) string {
+	raw := fmt.Sprintf("%d|%s", cursor.Time.Unix(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (db.Cursor, error) {
+	if cursor == "" {
+		return db.Cursor{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return db.Cursor{}, err
+	}
+
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return db.Cursor{}, errors.New("malformed cursor")
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return db.Cursor{}, err
+	}
+
+	return db.Cursor{Time: time.Unix(unix, 0), ID: id}, nil
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/labstack/echo/v4"
+	"github.com/user/project/internal/contract"
+	"github.com/user/project/internal/db"
+	"github.com/user/project/internal/terrors"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultFeedLimit = 20
+	maxFeedLimit     = 50
+)
+
+// GetFeed returns what the people the caller follows have done, newest first.
+func (a *API) GetFeed(c echo.Context) error {
+	ctx := c.Request().Context()
+	uid := GetContextUserID(c)
+
+	cursor, err := decodeCursor(c.QueryParam("cursor"))
+	if err != nil {
+		return terrors.BadRequest(err, "invalid cursor")
+	}
+
+	limit := defaultFeedLimit
+	if val := c.QueryParam("limit"); val != "" {
+		limit, err = strconv.Atoi(val)
+		if err != nil || limit <= 0 || limit > maxFeedLimit {
+			return terrors.BadRequest(err, fmt.Sprintf("limit must be between 1 and %d", maxFeedLimit))
+		}
+	}
+
+	events, err := a.storage.GetFeed(ctx, uid, cursor, limit)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get feed")
+	}
+
+	b := feedBuilder{api: a, users: map[string]contract.UserProfile{}, matches: map[string]db.Match{}}
+
+	resp := contract.FeedResponse{Items: make([]contract.FeedItem, 0, len(events))}
+	for _, event := range events {
+		item, err := b.build(ctx, event)
+		if err != nil && errors.Is(err, db.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return terrors.InternalServer(err, "failed to build feed")
+		}
+		resp.Items = append(resp.Items, item)
+	}
+
+	if len(events) == limit {
+		last := events[len(events)-1]
+		next := encodeCursor(db.Cursor{Time: last.OccurredAt, ID: last.ID})
+		resp.NextCursor = &next
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// feedBuilder turns feed events into response items, caching lookups within a page
+type feedBuilder struct {
+	api     *API
+	users   map[string]contract.UserProfile
+	matches map[string]db.Match
+}
+
+func (b *feedBuilder) build(ctx context.Context, event db.FeedEvent) (contract.FeedItem, error) {
+	item := contract.FeedItem{
+		ID:         event.ID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Points:     event.Points,
+		Position:   event.Position,
+	}
+
+	user, err := b.user(event.UserID)
+	if err != nil {
+		return item, err
+	}
+	item.User = user
+
+	switch event.Type {
+	case db.FeedEventPredictionPlaced, db.FeedEventPredictionSettled:
+		match, err := b.match(ctx, event.RelatedID)
+		if err != nil {
+			return item, err
+		}
+		matchResp := toMatchResponse(match)
+		item.Match = &matchResp
+
+		prediction, err := b.api.storage.GetUserPredictionByMatchID(ctx, event.UserID, event.RelatedID)
+		if err != nil {
+			return item, err
+		}
+
+		if !matchStarted(match, b.api.now()) {
+			maskPrediction(&prediction)
+			item.PredictionHidden = true
+		}
+		item.Prediction = &prediction
+	case db.FeedEventBadgeEarned:
+		badge, err := b.api.storage.GetBadgeByID(ctx, event.RelatedID)
+		if err != nil {
+			return item, err
+		}
+		item.Badge = &badge
+	case db.FeedEventSeasonPlacement:
+		season, err := b.api.storage.GetSeasonByID(ctx, event.RelatedID)
+		if err != nil {
+			return item, err
+		}
+		item.Season = &contract.SeasonResponse{
+			ID:        season.ID,
+			Name:      season.Name,
+			StartDate: season.StartDate,
+			EndDate:   season.EndDate,
+			IsActive:  season.IsActive,
+			Type:      season.Type,
+		}
+	}
+
+	return item, nil
+}
+
+func (b *feedBuilder) user(id string) (contract.UserProfile, error) {
+	if profile, ok := b.users[id]; ok {
+		return profile, nil
+	}
+
+	user, err := b.api.storage.GetUserByID(id)
+	if err != nil {
+		return contract.UserProfile{}, err
+	}
+
+	profile := contract.UserProfile{
+		ID:               user.ID,
+		FirstName:        user.FirstName,
+		LastName:         user.LastName,
+		Username:         user.Username,
+		AvatarURL:        user.AvatarURL,
+		FavoriteTeam:     user.FavoriteTeam,
+		CurrentWinStreak: user.CurrentWinStreak,
+		LongestWinStreak: user.LongestWinStreak,
+	}
+	b.users[id] = profile
+
+	return profile, nil
+}
+
+func (b *feedBuilder) match(ctx context.Context, id string) (db.Match, error) {
+	if match, ok := b.matches[id]; ok {
+		return match, nil
+	}
+
+	match, err := b.api.storage.GetMatchByID(ctx, id)
+	if err != nil {
+		return db.Match{}, err
+	}
+	b.matches[id] = match
+
+	return match, nil
+}
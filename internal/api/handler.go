@@ -4,8 +4,11 @@ import (
 	"context"
 	telegram "github.com/go-telegram/bot"
 	"github.com/labstack/echo/v4"
+	"github.com/user/project/internal/clock"
+	"github.com/user/project/internal/contract"
 	"github.com/user/project/internal/db"
 	"github.com/user/project/internal/s3"
+	"github.com/user/project/internal/scheduler"
 	"github.com/user/project/internal/terrors"
 	"net/http"
 	"time"
@@ -16,7 +19,8 @@ type storager interface {
 	Health() (db.HealthStats, error)
 	GetLeaderboard(ctx context.Context, seasonID string) ([]db.LeaderboardEntry, error)
 	AddPrediction(ctx context.Context, prediction db.Prediction) error
-	GetActiveMatches(ctx context.Context, userID string) ([]db.Match, error)
+	CountMatchdayPredictions(ctx context.Context, userID, competitionCode, season string, matchday int) (int, int, error)
+	GetActiveMatches(ctx context.Context, userID string, now time.Time, opts ...db.MatchFilter) ([]db.Match, error)
 	GetUserByChatID(chatID int64) (db.User, error)
 	GetUserByID(id string) (db.User, error)
 	GetUserByUsername(uname string) (db.User, error)
@@ -33,13 +37,27 @@ type storager interface {
 	ListTeams(ctx context.Context) ([]db.Team, error)
 	UpdateUserInformation(ctx context.Context, user db.User) error
 	GetUserRank(ctx context.Context, userID string) ([]db.Rank, error)
-	GetLastMatchesByTeamID(ctx context.Context, teamID string, limit int) ([]db.Match, error)
+	GetLastMatchesByTeamID(ctx context.Context, teamID string, before time.Time, limit int) ([]db.Match, error)
+	GetHeadToHead(ctx context.Context, teamID, opponentID string, before time.Time, limit int) ([]db.Match, error)
 	GetPredictionStats(ctx context.Context, userID string) (db.PredictionStats, error)
-	GetTodayMostPopularMatch(ctx context.Context) (db.Match, error)
+	GetTodayMostPopularMatch(ctx context.Context, from, to time.Time) (db.Match, error)
 	FollowUser(ctx context.Context, followerID, followeeID string) error
 	UnfollowUser(ctx context.Context, followerID, followeeID string) error
 	GetFollowers(ctx context.Context, userID string) ([]db.User, error)
 	GetFollowing(ctx context.Context, userID string) ([]db.User, error)
+	IsFollowing(ctx context.Context, followerID, followingID string) (bool, error)
+	SearchUsers(ctx context.Context, query string, limit int) ([]db.User, error)
+	SuggestUsersToFollow(ctx context.Context, userID string, limit int) ([]db.SuggestedUser, error)
+	ListCompetitions(ctx context.Context) ([]db.Competition, error)
+	SaveCompetition(ctx context.Context, c db.Competition) error
+	GetCompetitionByCode(ctx context.Context, code string) (db.Competition, error)
+	ListUnresolvedTeams(ctx context.Context) ([]db.UnresolvedTeam, error)
+	GetStandings(ctx context.Context, competitionCode string) ([]db.Standing, error)
+	GetOddsHistory(ctx context.Context, matchID string) ([]db.OddsSnapshot, error)
+	GetNotificationSettings(ctx context.Context, userID string) (db.NotificationSettings, error)
+	SaveNotificationSettings(ctx context.Context, settings db.NotificationSettings) error
+	AddTeamAlias(ctx context.Context, teamID, alias string) error
+	ListJobRuns(ctx context.Context, job string, limit int) ([]db.JobRun, error)
 	GetSurveyByUserAndFeature(ctx context.Context, userID, feature string) (db.Survey, error)
 	SaveSurvey(ctx context.Context, survey db.Survey) error
 	GetSurveyStats(ctx context.Context, feature string) (map[string]int, error)
@@ -51,6 +69,21 @@ type storager interface {
 	GetActiveSubscription(ctx context.Context, uid string) (db.Subscription, error)
 	SuspendSubscription(ctx context.Context, uid string) error
 	GetAllUsers(ctx context.Context) ([]db.User, error)
+	GetActiveSeason(ctx context.Context, seasonType string) (db.Season, error)
+	GetUserRankHistory(ctx context.Context, userID, seasonID string) ([]db.RankSnapshot, error)
+	ListBadges(ctx context.Context) ([]db.Badge, error)
+	CountExactScorePredictions(ctx context.Context, userID string) (int, error)
+	GetFeed(ctx context.Context, userID string, cursor db.Cursor, limit int) ([]db.FeedEvent, error)
+	SaveComment(ctx context.Context, comment db.Comment) error
+	ListMatchComments(ctx context.Context, matchID, viewerID string, cursor db.Cursor, limit int) ([]db.Comment, error)
+	GetCommentByID(ctx context.Context, id string) (db.Comment, error)
+	CountUserCommentsSince(ctx context.Context, userID string, since time.Time) (int, error)
+	AddCommentReaction(ctx context.Context, commentID, userID, emoji string) error
+	RemoveCommentReaction(ctx context.Context, commentID, userID, emoji string) error
+	ReportComment(ctx context.Context, commentID, userID, reason string) error
+	GetMatchCommentsVersion(ctx context.Context, matchID string) (string, error)
+	GetBadgeByID(ctx context.Context, id string) (db.Badge, error)
+	GetSeasonByID(ctx context.Context, id string) (db.Season, error)
 }
 
 type API struct {
@@ -65,9 +98,36 @@ type Config struct {
 	BotToken  string
 	AssetsURL string
 	OpenAIKey string
+	Location  *time.Location // League timezone used for "today"
+	Clock     clock.Clock
+
+	AdminUserIDs []string // Users allowed to call /admin endpoints
+
+	Scheduler     JobScheduler // Background jobs admins can inspect and trigger
+	Notifications Deliverer    // Sends messages to users, honoring their notification settings
+}
+
+// Deliverer is implemented by syncer.Syncer
+type Deliverer interface {
+	Deliver(ctx context.Context, user db.User, kind string, params contract.SendNotificationParams) (bool, error)
+}
+
+// JobScheduler is implemented by scheduler.Scheduler
+type JobScheduler interface {
+	Jobs() []scheduler.JobInfo
+	IsLeader() bool
+	RunNow(name string) error
 }
 
 func New(storage storager, cfg Config, s3Client *s3.Client, tgBot *telegram.Bot) *API {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+
+	if cfg.Clock == nil {
+		cfg.Clock = clock.Real()
+	}
+
 	return &API{
 		storage: storage,
 		cfg:     cfg,
@@ -76,6 +136,11 @@ func New(storage storager, cfg Config, s3Client *s3.Client, tgBot *telegram.Bot)
 	}
 }
 
+// now returns the current time in the league timezone
+func (a *API) now() time.Time {
+	return a.cfg.Clock.Now().In(a.cfg.Location)
+}
+
 func (a *API) Health(c echo.Context) error {
 	stats, err := a.storage.Health()
 	if err != nil {
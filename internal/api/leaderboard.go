@@ -12,6 +12,18 @@ import (
 
 func (a *API) GetLeaderboard(c echo.Context) error {
 	ctx := c.Request().Context()
+	uid := GetContextUserID(c)
+
+	// Followers-only profiles are shown to the people following them
+	followingUsers, err := a.storage.GetFollowing(ctx, uid)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get following")
+	}
+
+	following := make(map[string]bool, len(followingUsers))
+	for _, user := range followingUsers {
+		following[user.ID] = true
+	}
 
 	seasons, err := a.storage.GetActiveSeasons(ctx)
 	if err != nil {
@@ -46,6 +58,7 @@ func (a *API) GetLeaderboard(c echo.Context) error {
 				continue
 			}
 
+			userID := entry.UserID
 			userProfile := contract.UserProfile{
 				ID:               user.ID,
 				FirstName:        user.FirstName,
@@ -58,11 +71,25 @@ func (a *API) GetLeaderboard(c echo.Context) error {
 				Badges:           user.Badges,
 			}
 
+			anonymous := !profileVisible(uid, following, user)
+			if anonymous {
+				// Keep the place in the table but not who holds it
+				userID = ""
+				userProfile = contract.UserProfile{}
+			}
+
 			leaderboard[idx] = contract.LeaderboardEntry{
-				User:     userProfile,
-				UserID:   entry.UserID,
-				Points:   entry.Points,
-				SeasonID: entry.SeasonID,
+				User:             userProfile,
+				Anonymous:        anonymous,
+				UserID:           userID,
+				Points:           entry.Points,
+				SeasonID:         entry.SeasonID,
+				Position:         entry.Position,
+				PreviousPosition: entry.PreviousPosition,
+			}
+
+			if entry.PreviousPosition != nil {
+				leaderboard[idx].RankDelta = *entry.PreviousPosition - entry.Position
 			}
 		}
 
@@ -91,3 +118,40 @@ func (a *API) GetLeaderboard(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, response)
 }
+
+func (a *API) GetUserRankHistory(c echo.Context) error {
+	username := c.Param("username")
+	ctx := c.Request().Context()
+
+	user, err := a.storage.GetUserByUsername(username)
+	if err != nil && errors.Is(err, db.ErrNotFound) {
+		return terrors.NotFound(err, "user not found")
+	} else if err != nil {
+		return terrors.InternalServer(err, "failed to get user")
+	}
+
+	visible, err := a.canViewProfile(ctx, GetContextUserID(c), user)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to check profile privacy")
+	} else if !visible {
+		return terrors.Forbidden(errors.New("profile is not visible"), "this profile is private")
+	}
+
+	seasonID := c.QueryParam("season_id")
+	if seasonID == "" {
+		season, err := a.storage.GetActiveSeason(ctx, db.SeasonTypeMonthly)
+		if err != nil && errors.Is(err, db.ErrNotFound) {
+			return terrors.NotFound(err, "no active season")
+		} else if err != nil {
+			return terrors.InternalServer(err, "failed to get active season")
+		}
+		seasonID = season.ID
+	}
+
+	history, err := a.storage.GetUserRankHistory(ctx, user.ID, seasonID)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get rank history")
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
@@ -3,10 +3,13 @@ package api
 import (
 	"errors"
 	"github.com/labstack/echo/v4"
+	"github.com/user/project/internal/clock"
 	"github.com/user/project/internal/contract"
 	"github.com/user/project/internal/db"
 	"github.com/user/project/internal/terrors"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 func (a *API) GetMatchByID(c echo.Context) error {
@@ -30,22 +33,144 @@ func (a *API) GetMatchByID(c echo.Context) error {
 		match.Prediction = &prediction
 	}
 
+	headToHeadLimit := defaultHeadToHeadLimit
+	if val := c.QueryParam("h2h"); val != "" {
+		headToHeadLimit, err = strconv.Atoi(val)
+		if err != nil || headToHeadLimit < 0 || headToHeadLimit > maxHeadToHeadLimit {
+			return terrors.BadRequest(err, "h2h must be a number between 0 and 20")
+		}
+	}
+
 	//stats, err := a.storage.GetPredictionStats(ctx, matchID)
 	//if err != nil {
 	//	return terrors.InternalServer(err, "failed to get prediction stats")
 	//}
 
-	//response := toMatchResponse(match)
+	response := toMatchResponse(match)
 	//response.PredictionStats = stats
 
-	return c.JSON(http.StatusOK, toMatchResponse(match))
+	homeForm, err := a.storage.GetLastMatchesByTeamID(ctx, match.HomeTeamID, match.MatchDate, teamFormLength)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get home team form")
+	}
+
+	awayForm, err := a.storage.GetLastMatchesByTeamID(ctx, match.AwayTeamID, match.MatchDate, teamFormLength)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get away team form")
+	}
+
+	response.HomeTeamForm, response.HomeTeamResults = toTeamForm(match.HomeTeamID, homeForm)
+	response.AwayTeamForm, response.AwayTeamResults = toTeamForm(match.AwayTeamID, awayForm)
+
+	if headToHeadLimit > 0 {
+		meetings, err := a.storage.GetHeadToHead(ctx, match.HomeTeamID, match.AwayTeamID, match.MatchDate, headToHeadLimit)
+		if err != nil {
+			return terrors.InternalServer(err, "failed to get head to head")
+		}
+
+		response.HeadToHead = toHeadToHead(match.HomeTeamID, meetings)
+	}
+
+	return c.JSON(http.StatusOK, response)
 }
 
+const (
+	teamFormLength         = 5
+	defaultHeadToHeadLimit = 5
+	maxHeadToHeadLimit     = 20
+)
+
+// toTeamForm describes completed matches from the team's side, along with the bare W/D/L sequence
+func toTeamForm(teamID string, matches []db.Match) ([]contract.FormResult, []string) {
+	form := make([]contract.FormResult, 0, len(matches))
+	results := make([]string, 0, len(matches))
+	for _, m := range matches {
+		if m.HomeScore == nil || m.AwayScore == nil {
+			continue
+		}
+
+		r := contract.FormResult{
+			MatchID:      m.ID,
+			MatchDate:    m.MatchDate,
+			Tournament:   m.Tournament,
+			Home:         m.HomeTeamID == teamID,
+			GoalsFor:     *m.HomeScore,
+			GoalsAgainst: *m.AwayScore,
+			Opponent:     m.AwayTeam,
+		}
+		if !r.Home {
+			r.GoalsFor, r.GoalsAgainst = r.GoalsAgainst, r.GoalsFor
+			r.Opponent = m.HomeTeam
+		}
+
+		switch {
+		case r.GoalsFor > r.GoalsAgainst:
+			r.Result = "W"
+		case r.GoalsFor < r.GoalsAgainst:
+			r.Result = "L"
+		default:
+			r.Result = "D"
+		}
+
+		form = append(form, r)
+		results = append(results, r.Result)
+	}
+
+	return form, results
+}
+
+// toHeadToHead counts wins relative to the current home team, whichever side it played on back then
+func toHeadToHead(homeTeamID string, matches []db.Match) *contract.HeadToHead {
+	h2h := &contract.HeadToHead{Matches: matches}
+	for _, m := range matches {
+		if m.HomeScore == nil || m.AwayScore == nil {
+			continue
+		}
+
+		homeGoals, awayGoals := *m.HomeScore, *m.AwayScore
+		if m.HomeTeamID != homeTeamID {
+			homeGoals, awayGoals = awayGoals, homeGoals
+		}
+
+		switch {
+		case homeGoals > awayGoals:
+			h2h.HomeWins++
+		case homeGoals < awayGoals:
+			h2h.AwayWins++
+		default:
+			h2h.Draws++
+		}
+	}
+
+	return h2h
+}
+
+// ListMatches returns the upcoming week of matches, or a whole round of the
+// competition's current season when filtered by matchday or stage
 func (a *API) ListMatches(c echo.Context) error {
 	ctx := c.Request().Context()
 	uid := GetContextUserID(c)
-	matches, err := a.storage.GetActiveMatches(ctx, uid)
 
+	var filters []db.MatchFilter
+	if val := c.QueryParam("competition"); val != "" {
+		filters = append(filters, db.WithCompetition(strings.ToUpper(val)))
+	}
+	if val := c.QueryParam("matchday"); val != "" {
+		matchday, err := strconv.Atoi(val)
+		if err != nil || matchday <= 0 {
+			return terrors.BadRequest(err, "matchday must be a positive number")
+		}
+		filters = append(filters, db.WithMatchday(matchday))
+	}
+	if val := c.QueryParam("stage"); val != "" {
+		filters = append(filters, db.WithStage(strings.ToUpper(val)))
+	}
+
+	matches, err := a.storage.GetActiveMatches(ctx, uid, a.now(), filters...)
+
+	if errors.Is(err, db.ErrCompetitionRequired) {
+		return terrors.BadRequest(err, "competition is required to filter by matchday or stage")
+	}
 	if err != nil {
 		return terrors.InternalServer(err, "failed to get active matches")
 	}
@@ -67,6 +192,22 @@ func toMatchResponse(match db.Match) contract.MatchResponse {
 		HomeOdds:   match.HomeOdds,
 		DrawOdds:   match.DrawOdds,
 		AwayOdds:   match.AwayOdds,
+		Competition: contract.CompetitionInfo{
+			Code:   match.CompetitionCode,
+			Name:   match.Tournament,
+			Emblem: match.CompetitionEmblem,
+		},
+		Area: contract.AreaInfo{
+			Code: match.AreaCode,
+			Name: match.AreaName,
+		},
+		Matchday: match.Matchday,
+		Stage:    match.Stage,
+		Group:    match.Group,
+		Referees: match.Referees,
+
+		HomeTeamPosition: match.HomeTeamPosition,
+		AwayTeamPosition: match.AwayTeamPosition,
 	}
 }
 
@@ -75,10 +216,60 @@ func toMatchResponse(match db.Match) contract.MatchResponse {
 func (a *API) GetTodayMostPopularMatch(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	match, err := a.storage.GetTodayMostPopularMatch(ctx)
+	now := a.now()
+	endOfDay := clock.StartOfDay(now, a.cfg.Location).AddDate(0, 0, 1)
+
+	match, err := a.storage.GetTodayMostPopularMatch(ctx, now, endOfDay)
 	if err != nil {
 		return terrors.InternalServer(err, "failed to get most popular match")
 	}
 
 	return c.JSON(http.StatusOK, toMatchResponse(match))
 }
+
+// GetMatchOdds returns the timeline of the match's odds
+func (a *API) GetMatchOdds(c echo.Context) error {
+	ctx := c.Request().Context()
+	matchID := c.Param("id")
+
+	if _, err := a.storage.GetMatchByID(ctx, matchID); err != nil && errors.Is(err, db.ErrNotFound) {
+		return terrors.NotFound(err, "match not found")
+	} else if err != nil {
+		return terrors.InternalServer(err, "failed to get match")
+	}
+
+	snapshots, err := a.storage.GetOddsHistory(ctx, matchID)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get odds history")
+	}
+
+	res := contract.OddsHistoryResponse{
+		MatchID:   matchID,
+		Snapshots: snapshots,
+	}
+	if len(snapshots) > 0 {
+		res.Opening = &snapshots[0]
+		res.Latest = &snapshots[len(snapshots)-1]
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetCompetitionStandings returns the competition's league table, or group tables for cup stages
+func (a *API) GetCompetitionStandings(c echo.Context) error {
+	ctx := c.Request().Context()
+	code := strings.ToUpper(c.Param("code"))
+
+	if _, err := a.storage.GetCompetitionByCode(ctx, code); err != nil && errors.Is(err, db.ErrNotFound) {
+		return terrors.NotFound(err, "competition not found")
+	} else if err != nil {
+		return terrors.InternalServer(err, "failed to get competition")
+	}
+
+	standings, err := a.storage.GetStandings(ctx, code)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get standings")
+	}
+
+	return c.JSON(http.StatusOK, standings)
+}
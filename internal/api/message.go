@@ -2,15 +2,31 @@ package api
 
 import (
 	"fmt"
-	"github.com/go-telegram/bot"
-	"github.com/go-telegram/bot/models"
 	"github.com/labstack/echo/v4"
+	"github.com/user/project/internal/contract"
 	"github.com/user/project/internal/db"
+	"io"
 	"log"
+	"net/http"
 	"sync"
 	"time"
 )
 
+// downloadImage fetches the image broadcasts are sent with
+func downloadImage(imgURL string) ([]byte, error) {
+	resp, err := http.Get(imgURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download image: status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
 func (a *API) BroadcastSubscriptionMessage(c echo.Context) error {
 	isTestRun := c.QueryParam("test") == "true"
 	if isTestRun {
@@ -36,6 +52,12 @@ To place bets, you\’ll need a subscription for *150 Telegram Stars* per month\
 
 🔥 Subscribe now and unleash your *football instincts*\!`
 
+	image, err := downloadImage("https://assets.peatch.io/preview.png")
+	if err != nil {
+		log.Printf("Failed to get broadcast image: %v", err)
+		return err
+	}
+
 	batchSize := 15
 	var wg sync.WaitGroup
 	semaphore := make(chan struct{}, batchSize)
@@ -60,31 +82,18 @@ To place bets, you\’ll need a subscription for *150 Telegram Stars* per month\
 					message = messageEn
 				}
 
-				msg := bot.SendPhotoParams{
-					ChatID:    user.ChatID,
-					Caption:   message,
-					ParseMode: models.ParseModeMarkdown,
-					Photo:     &models.InputFileString{Data: "https://assets.peatch.io/preview.png"},
-					ReplyMarkup: &models.InlineKeyboardMarkup{
-						InlineKeyboard: [][]models.InlineKeyboardButton{
-							{
-								{
-									Text: "В приложение",
-									WebApp: &models.WebAppInfo{
-										URL: "https://fleague.mxksimdev.com",
-									},
-								},
-							},
-						},
-					},
-				}
-
 				if user.ChatID != 927635965 && isTestRun {
 					log.Printf("Test run skipped for user %s", user.ID)
 					return
 				}
 
-				_, err := a.tg.SendPhoto(c.Request().Context(), &msg)
+				// Preferences and quiet hours are handled the same way as for every other notification
+				_, err := a.cfg.Notifications.Deliver(c.Request().Context(), user, db.NotificationMarketing, contract.SendNotificationParams{
+					Message:    message,
+					Image:      image,
+					WebAppURL:  "https://fleague.mxksimdev.com",
+					ButtonText: "В приложение",
+				})
 				if err != nil {
 					log.Printf("Failed to send message to user %s: %v", user.ID, err)
 				}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/user/project/internal/contract"
+	"github.com/user/project/internal/db"
+	"github.com/user/project/internal/terrors"
+)
+
+func (a *API) GetNotificationSettings(c echo.Context) error {
+	uid := GetContextUserID(c)
+
+	settings, err := a.storage.GetNotificationSettings(c.Request().Context(), uid)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get notification settings")
+	}
+
+	return c.JSON(http.StatusOK, a.toNotificationSettingsResponse(settings))
+}
+
+func (a *API) UpdateNotificationSettings(c echo.Context) error {
+	var req contract.NotificationSettingsRequest
+	if err := c.Bind(&req); err != nil {
+		return terrors.BadRequest(err, "failed to decode request")
+	}
+
+	if err := req.Validate(); err != nil {
+		return terrors.BadRequest(err, "failed to validate request")
+	}
+
+	uid := GetContextUserID(c)
+	ctx := c.Request().Context()
+
+	settings, err := a.storage.GetNotificationSettings(ctx, uid)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get notification settings")
+	}
+
+	for _, field := range []struct {
+		value *bool
+		dest  *bool
+	}{
+		{req.FavoriteTeam, &settings.FavoriteTeam},
+		{req.Reminders, &settings.Reminders},
+		{req.Streaks, &settings.Streaks},
+		{req.Results, &settings.Results},
+		{req.Recaps, &settings.Recaps},
+		{req.Marketing, &settings.Marketing},
+	} {
+		if field.value != nil {
+			*field.dest = *field.value
+		}
+	}
+
+	if req.QuietHoursStart != nil {
+		settings.QuietHoursStart, settings.QuietHoursEnd = nil, nil
+		if *req.QuietHoursStart != "" {
+			settings.QuietHoursStart, settings.QuietHoursEnd = req.QuietHoursStart, req.QuietHoursEnd
+		}
+	}
+
+	if req.Timezone != nil {
+		settings.Timezone = nil
+		if *req.Timezone != "" {
+			settings.Timezone = req.Timezone
+		}
+	}
+
+	if err := a.storage.SaveNotificationSettings(ctx, settings); err != nil {
+		return terrors.InternalServer(err, "failed to save notification settings")
+	}
+
+	return c.JSON(http.StatusOK, a.toNotificationSettingsResponse(settings))
+}
+
+func (a *API) toNotificationSettingsResponse(settings db.NotificationSettings) contract.NotificationSettingsResponse {
+	return contract.NotificationSettingsResponse{
+		FavoriteTeam:    settings.FavoriteTeam,
+		Reminders:       settings.Reminders,
+		Streaks:         settings.Streaks,
+		Results:         settings.Results,
+		Recaps:          settings.Recaps,
+		Marketing:       settings.Marketing,
+		QuietHoursStart: settings.QuietHoursStart,
+		QuietHoursEnd:   settings.QuietHoursEnd,
+		Timezone:        settings.Location(a.cfg.Location).String(),
+	}
+}
@@ -29,7 +29,7 @@ func (a *API) SavePrediction(c echo.Context) error {
 		return terrors.InternalServer(err, "failed to get user")
 	}
 
-	if !user.SubscriptionActive || user.SubscriptionExpiry.Before(time.Now()) {
+	if !user.SubscriptionActive || user.SubscriptionExpiry.Before(a.now()) {
 		return ErrNoActiveSubscription
 	}
 
@@ -50,7 +50,10 @@ func (a *API) SavePrediction(c echo.Context) error {
 		PredictedHomeScore: req.PredictedHomeScore,
 		PredictedAwayScore: req.PredictedAwayScore,
 	}
-	if err := a.storage.SavePrediction(ctx, prediction); err != nil {
+	err = a.storage.SavePrediction(ctx, prediction)
+	if err != nil && errors.Is(err, db.ErrNotFound) {
+		return terrors.BadRequest(nil, "match not found")
+	} else if err != nil {
 		return err
 	}
 
@@ -100,7 +103,8 @@ func (a *API) GetUserPredictions(c echo.Context) error {
 	resp, err := a.predictionsByUserID(
 		ctx,
 		uid,
-		db.WithStartTime(time.Now().Add(-7*24*time.Hour)),
+		uid,
+		db.WithStartTime(a.now().Add(-7*24*time.Hour)),
 		db.WithLimit(100),
 	)
 
@@ -109,3 +113,15 @@ func (a *API) GetUserPredictions(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, resp)
 }
+
+// matchStarted reports whether picks on the match can be shown to other users
+func matchStarted(match db.Match, now time.Time) bool {
+	return match.Status != db.MatchStatusScheduled || !match.MatchDate.After(now)
+}
+
+// maskPrediction hides the pick itself, leaving only the fact that a prediction was made
+func maskPrediction(prediction *db.Prediction) {
+	prediction.PredictedOutcome = nil
+	prediction.PredictedHomeScore = nil
+	prediction.PredictedAwayScore = nil
+}
@@ -13,7 +13,6 @@ import (
 	"log"
 	"net/http"
 	"strings"
-	"time"
 )
 
 const OneMonthInSeconds = 2592000
@@ -127,7 +126,7 @@ func (a *API) HandleSuccessfulPayment(update models.Update) error {
 	}
 
 	// Рассчитываем новую дату окончания подписки
-	now := time.Now()
+	now := a.now()
 	newExpiry := now.AddDate(0, 1, 0) // +1 месяц
 	if user.SubscriptionActive && user.SubscriptionExpiry.After(now) {
 		// Если подписка уже активна, добавляем 30 дней к текущей дате окончания
@@ -13,6 +13,8 @@ import (
 	"net/http"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -30,7 +32,9 @@ func GetContextUserID(c echo.Context) string {
 	return claims.UID
 }
 
-func (a *API) predictionsByUserID(ctx context.Context, uid string, filter ...db.PredictionFilter) ([]contract.PredictionResponse, error) {
+// predictionsByUserID returns uid's predictions as seen by viewerID; picks on matches
+// that haven't kicked off yet are masked for everyone but the owner.
+func (a *API) predictionsByUserID(ctx context.Context, viewerID, uid string, filter ...db.PredictionFilter) ([]contract.PredictionResponse, error) {
 	predictions, err := a.storage.GetPredictionsByUserID(ctx, uid, filter...)
 	if err != nil {
 		return nil, err
@@ -45,16 +49,25 @@ func (a *API) predictionsByUserID(ctx context.Context, uid string, filter ...db.
 			return nil, err
 		}
 
+		hidden := viewerID != uid && !matchStarted(match, a.now())
+		if hidden {
+			maskPrediction(&prediction)
+		}
+
 		res = append(res, contract.PredictionResponse{
 			UserID:             prediction.UserID,
 			MatchID:            prediction.MatchID,
 			PredictedOutcome:   prediction.PredictedOutcome,
 			PredictedHomeScore: prediction.PredictedHomeScore,
 			PredictedAwayScore: prediction.PredictedAwayScore,
+			HomeOdds:           prediction.HomeOdds,
+			DrawOdds:           prediction.DrawOdds,
+			AwayOdds:           prediction.AwayOdds,
 			PointsAwarded:      prediction.PointsAwarded,
 			CreatedAt:          prediction.CreatedAt,
 			CompletedAt:        prediction.CompletedAt,
 			Match:              toMatchResponse(match),
+			Hidden:             hidden,
 		})
 	}
 
@@ -66,9 +79,42 @@ func (a *API) predictionsByUserID(ctx context.Context, uid string, filter ...db.
 	return res, nil
 }
 
+// canViewProfile checks the user's privacy setting against the viewer
+func (a *API) canViewProfile(ctx context.Context, viewerID string, user db.User) (bool, error) {
+	if viewerID == user.ID {
+		return true, nil
+	}
+
+	switch user.Privacy {
+	case db.PrivacyPrivate:
+		return false, nil
+	case db.PrivacyFollowers:
+		return a.storage.IsFollowing(ctx, viewerID, user.ID)
+	default:
+		return true, nil
+	}
+}
+
+// profileVisible is canViewProfile for bulk checks, with the viewer's followings loaded upfront
+func profileVisible(viewerID string, following map[string]bool, user db.User) bool {
+	if viewerID == user.ID {
+		return true
+	}
+
+	switch user.Privacy {
+	case db.PrivacyPrivate:
+		return false
+	case db.PrivacyFollowers:
+		return following[user.ID]
+	default:
+		return true
+	}
+}
+
 func (a *API) GetUserInfo(c echo.Context) error {
 	username := c.Param("username")
 	ctx := c.Request().Context()
+	uid := GetContextUserID(c)
 
 	user, err := a.storage.GetUserByUsername(username)
 
@@ -78,10 +124,30 @@ func (a *API) GetUserInfo(c echo.Context) error {
 		return terrors.InternalServer(err, "failed to get user")
 	}
 
+	visible, err := a.canViewProfile(ctx, uid, user)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to check profile privacy")
+	}
+
+	if !visible {
+		return c.JSON(http.StatusOK, &contract.UserInfoResponse{
+			User: contract.UserProfile{
+				ID:        user.ID,
+				FirstName: user.FirstName,
+				LastName:  user.LastName,
+				Username:  user.Username,
+				AvatarURL: user.AvatarURL,
+			},
+			Predictions:   []contract.PredictionResponse{},
+			ProfileHidden: true,
+		})
+	}
+
 	userPredictions, err := a.predictionsByUserID(
 		ctx,
+		uid,
 		user.ID,
-		db.WithStartTime(time.Now().Add(-7*24*time.Hour)),
+		db.WithStartTime(a.now().Add(-7*24*time.Hour)),
 		db.WithLimit(100),
 	)
 
@@ -172,6 +238,10 @@ func (a *API) UpdateUser(c echo.Context) error {
 		user.AvatarURL = req.AvatarURL
 	}
 
+	if req.Privacy != nil {
+		user.Privacy = *req.Privacy
+	}
+
 	if err := a.storage.UpdateUserInformation(ctx, user); err != nil {
 		return terrors.InternalServer(err, "could not update user")
 	}
@@ -310,3 +380,72 @@ func (a *API) GetFollowingHandler(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, users)
 }
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+)
+
+// SearchUsers finds people by username or name. Only public identity is returned,
+// stats stay behind the profile's privacy setting.
+func (a *API) SearchUsers(c echo.Context) error {
+	query := strings.TrimSpace(c.QueryParam("q"))
+	if query == "" {
+		return terrors.BadRequest(errors.New("empty query"), "query is required")
+	}
+
+	limit := defaultSearchLimit
+	if val := c.QueryParam("limit"); val != "" {
+		var err error
+		limit, err = strconv.Atoi(val)
+		if err != nil || limit <= 0 || limit > maxSearchLimit {
+			return terrors.BadRequest(err, fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit))
+		}
+	}
+
+	users, err := a.storage.SearchUsers(c.Request().Context(), query, limit)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to search users")
+	}
+
+	res := make([]contract.UserProfile, 0, len(users))
+	for _, user := range users {
+		res = append(res, contract.UserProfile{
+			ID:        user.ID,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Username:  user.Username,
+			AvatarURL: user.AvatarURL,
+		})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetFollowSuggestions returns people the caller might want to follow
+func (a *API) GetFollowSuggestions(c echo.Context) error {
+	suggestions, err := a.storage.SuggestUsersToFollow(c.Request().Context(), GetContextUserID(c), defaultSearchLimit)
+	if err != nil {
+		return terrors.InternalServer(err, "failed to get suggestions")
+	}
+
+	res := make([]contract.SuggestedUserResponse, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		profile := contract.UserProfile{
+			ID:        suggestion.ID,
+			FirstName: suggestion.FirstName,
+			LastName:  suggestion.LastName,
+			Username:  suggestion.Username,
+			AvatarURL: suggestion.AvatarURL,
+		}
+		// Suggested people aren't followed yet, so followers-only stats stay hidden
+		if suggestion.Privacy == db.PrivacyPublic {
+			profile.TotalPredictions = suggestion.TotalPredictions
+			profile.CorrectPredictions = suggestion.CorrectPredictions
+		}
+
+		res = append(res, contract.SuggestedUserResponse{User: profile, Reason: suggestion.Reason})
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/user/project/internal/db"
+	"github.com/user/project/internal/scheduler"
 	"net/url"
 	"strings"
 	"time"
@@ -39,6 +40,7 @@ type UserResponse struct {
 	PredictionAccuracy float64    `json:"prediction_accuracy"`
 	SubscriptionActive bool       `json:"subscription_active"`
 	SubscriptionExpiry *time.Time `json:"subscription_expiry"`
+	Privacy            string     `json:"privacy"`
 }
 
 type PredictionResponse struct {
@@ -47,11 +49,15 @@ type PredictionResponse struct {
 	PredictedOutcome   *string       `json:"predicted_outcome"`
 	PredictedHomeScore *int          `json:"predicted_home_score"`
 	PredictedAwayScore *int          `json:"predicted_away_score"`
+	HomeOdds           *float64      `json:"home_odds"` // Match odds when the prediction was saved
+	DrawOdds           *float64      `json:"draw_odds"`
+	AwayOdds           *float64      `json:"away_odds"`
 	PointsAwarded      int           `json:"points_awarded"`
 	CreatedAt          time.Time     `json:"created_at"`
 	UpdatedAt          time.Time     `json:"updated_at"`
 	CompletedAt        *time.Time    `json:"completed_at"`
 	Match              MatchResponse `json:"match"`
+	Hidden             bool          `json:"hidden"` // Pick is masked until kickoff, only the fact of predicting is shown
 }
 
 type PredictionRequest struct {
@@ -96,6 +102,59 @@ type MatchResponse struct {
 	HomeTeamResults []string           `json:"home_team_results"`
 	AwayTeamResults []string           `json:"away_team_results"`
 	PredictionStats db.PredictionStats `json:"prediction_stats"`
+
+	Competition CompetitionInfo `json:"competition"`
+	Area        AreaInfo        `json:"area"`
+	Matchday    *int            `json:"matchday"`
+	Stage       string          `json:"stage"`
+	Group       *string         `json:"group"`
+	Referees    []db.Referee    `json:"referees"`
+
+	HomeTeamPosition *int `json:"home_team_position"`
+	AwayTeamPosition *int `json:"away_team_position"`
+
+	HomeTeamForm []FormResult `json:"home_team_form"`
+	AwayTeamForm []FormResult `json:"away_team_form"`
+	HeadToHead   *HeadToHead  `json:"head_to_head,omitempty"`
+}
+
+// FormResult is one of a team's recent matches, seen from that team's side
+type FormResult struct {
+	MatchID      string    `json:"match_id"`
+	MatchDate    time.Time `json:"match_date"`
+	Tournament   string    `json:"tournament"`
+	Opponent     db.Team   `json:"opponent"`
+	Home         bool      `json:"home"`
+	GoalsFor     int       `json:"goals_for"`
+	GoalsAgainst int       `json:"goals_against"`
+	Result       string    `json:"result"` // W, D or L
+}
+
+// HeadToHead summarises previous meetings, wins counted for the current home and away teams
+type HeadToHead struct {
+	HomeWins int        `json:"home_wins"`
+	Draws    int        `json:"draws"`
+	AwayWins int        `json:"away_wins"`
+	Matches  []db.Match `json:"matches"`
+}
+
+// OddsHistoryResponse is how a match's odds moved, one snapshot per change, oldest first
+type OddsHistoryResponse struct {
+	MatchID   string            `json:"match_id"`
+	Opening   *db.OddsSnapshot  `json:"opening"`
+	Latest    *db.OddsSnapshot  `json:"latest"`
+	Snapshots []db.OddsSnapshot `json:"snapshots"`
+}
+
+type CompetitionInfo struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Emblem string `json:"emblem"`
+}
+
+type AreaInfo struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
 }
 
 type UserProfile struct {
@@ -115,15 +174,146 @@ type UserProfile struct {
 }
 
 type LeaderboardEntry struct {
-	UserID   string      `json:"user_id"`
-	Points   int         `json:"points"`
-	SeasonID string      `json:"season_id"`
-	User     UserProfile `json:"user"`
+	UserID           string      `json:"user_id"`
+	Points           int         `json:"points"`
+	SeasonID         string      `json:"season_id"`
+	Position         int         `json:"position"`
+	PreviousPosition *int        `json:"previous_position"`
+	RankDelta        int         `json:"rank_delta"` // Positive when the user moved up since the last snapshot
+	User             UserProfile `json:"user"`
+	Anonymous        bool        `json:"anonymous"` // The user's privacy settings hide who they are from the viewer
+}
+
+type AchievementResponse struct {
+	Badge     db.Badge   `json:"badge"`
+	Current   int        `json:"current"`
+	Target    int        `json:"target"`
+	Unit      string     `json:"unit"` // predictions, streak, position or matches
+	Earned    bool       `json:"earned"`
+	AwardedAt *time.Time `json:"awarded_at"`
+}
+
+type FeedItem struct {
+	ID               string          `json:"id"`
+	Type             string          `json:"type"`
+	OccurredAt       time.Time       `json:"occurred_at"`
+	User             UserProfile     `json:"user"`
+	Match            *MatchResponse  `json:"match,omitempty"`
+	Prediction       *db.Prediction  `json:"prediction,omitempty"`
+	PredictionHidden bool            `json:"prediction_hidden"` // Picks stay hidden until kickoff
+	Badge            *db.Badge       `json:"badge,omitempty"`
+	Season           *SeasonResponse `json:"season,omitempty"`
+	Points           *int            `json:"points,omitempty"`
+	Position         *int            `json:"position,omitempty"`
+}
+
+type FeedResponse struct {
+	Items      []FeedItem `json:"items"`
+	NextCursor *string    `json:"next_cursor"`
+}
+
+type CommentResponse struct {
+	ID        string             `json:"id"`
+	MatchID   string             `json:"match_id"`
+	User      UserProfile        `json:"user"`
+	Body      string             `json:"body"`
+	IsSpoiler bool               `json:"is_spoiler"`
+	Hidden    bool               `json:"hidden"` // Spoiler body is hidden until kickoff
+	CreatedAt time.Time          `json:"created_at"`
+	Reactions []db.ReactionCount `json:"reactions"`
+}
+
+type CommentsResponse struct {
+	Items      []CommentResponse `json:"items"`
+	NextCursor *string           `json:"next_cursor"`
+}
+
+const MaxCommentLength = 1000
+
+type CommentRequest struct {
+	Body      string `json:"body"`
+	IsSpoiler bool   `json:"is_spoiler"`
+}
+
+func (r CommentRequest) Validate() error {
+	if strings.TrimSpace(r.Body) == "" {
+		return errors.New("comment cannot be empty")
+	}
+
+	if len([]rune(r.Body)) > MaxCommentLength {
+		return fmt.Errorf("comment cannot be longer than %d characters", MaxCommentLength)
+	}
+
+	return nil
+}
+
+// CommentReactions is the set of emoji users can react with
+var CommentReactions = map[string]bool{
+	"👍": true,
+	"👎": true,
+	"🔥": true,
+	"😂": true,
+	"😮": true,
+	"⚽": true,
+}
+
+type ReactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+func (r ReactionRequest) Validate() error {
+	if !CommentReactions[r.Emoji] {
+		return errors.New("unsupported reaction")
+	}
+	return nil
+}
+
+type ReportCommentRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (r ReportCommentRequest) Validate() error {
+	if len([]rune(r.Reason)) > 500 {
+		return errors.New("reason cannot be longer than 500 characters")
+	}
+	return nil
+}
+
+type CompetitionRequest struct {
+	Name         string  `json:"name"`
+	Emblem       *string `json:"emblem"`
+	Area         *string `json:"area"`
+	Enabled      *bool   `json:"enabled"` // Defaults to true
+	SyncPriority int     `json:"sync_priority"`
+}
+
+func (r CompetitionRequest) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+type TeamAliasRequest struct {
+	Alias string `json:"alias"`
+}
+
+func (r TeamAliasRequest) Validate() error {
+	if strings.TrimSpace(r.Alias) == "" {
+		return errors.New("alias is required")
+	}
+	return nil
+}
+
+type SuggestedUserResponse struct {
+	User   UserProfile `json:"user"`
+	Reason string      `json:"reason"` // same_team or top_predictor
 }
 
 type UserInfoResponse struct {
-	User        UserProfile          `json:"user"`
-	Predictions []PredictionResponse `json:"predictions"`
+	User          UserProfile          `json:"user"`
+	Predictions   []PredictionResponse `json:"predictions"`
+	ProfileHidden bool                 `json:"profile_hidden"` // Privacy settings don't allow the viewer to see stats and predictions
 }
 
 type SeasonResponse struct {
@@ -165,6 +355,7 @@ type UpdateUserRequest struct {
 	FavoriteTeamID *string `json:"favorite_team_id"`
 	LanguageCode   *string `json:"language_code"`
 	AvatarURL      *string `json:"avatar_url"`
+	Privacy        *string `json:"privacy"`
 }
 
 func (u UpdateUserRequest) Validate() error {
@@ -184,6 +375,10 @@ func (u UpdateUserRequest) Validate() error {
 		return fmt.Errorf("language code must be ru or en")
 	}
 
+	if u.Privacy != nil && *u.Privacy != db.PrivacyPublic && *u.Privacy != db.PrivacyFollowers && *u.Privacy != db.PrivacyPrivate {
+		return fmt.Errorf("privacy must be public, followers or private")
+	}
+
 	if u.AvatarURL != nil {
 		if *u.AvatarURL == "" {
 			return fmt.Errorf("avatar url cannot be empty")
@@ -235,3 +430,67 @@ func (r *SurveyRequest) Validate() error {
 
 	return nil
 }
+
+// SyncStatusResponse is what admins check when scores stop updating
+type SyncStatusResponse struct {
+	Runs         []db.JobRun      `json:"runs"`
+	Competitions []db.Competition `json:"competitions"` // last_synced_at is the last successful sync
+}
+
+type JobsResponse struct {
+	Leader bool                `json:"leader"` // Whether this instance runs the jobs
+	Jobs   []scheduler.JobInfo `json:"jobs"`
+}
+
+type NotificationSettingsResponse struct {
+	FavoriteTeam    bool    `json:"favorite_team"`
+	Reminders       bool    `json:"reminders"`
+	Streaks         bool    `json:"streaks"`
+	Results         bool    `json:"results"`
+	Recaps          bool    `json:"recaps"`
+	Marketing       bool    `json:"marketing"`
+	QuietHoursStart *string `json:"quiet_hours_start"` // HH:MM, nil without quiet hours
+	QuietHoursEnd   *string `json:"quiet_hours_end"`
+	Timezone        string  `json:"timezone"` // The league timezone unless the user picked one
+}
+
+// NotificationSettingsRequest changes the fields that are set. Empty quiet hours
+// turn them off, an empty timezone goes back to the league timezone.
+type NotificationSettingsRequest struct {
+	FavoriteTeam    *bool   `json:"favorite_team"`
+	Reminders       *bool   `json:"reminders"`
+	Streaks         *bool   `json:"streaks"`
+	Results         *bool   `json:"results"`
+	Recaps          *bool   `json:"recaps"`
+	Marketing       *bool   `json:"marketing"`
+	QuietHoursStart *string `json:"quiet_hours_start"`
+	QuietHoursEnd   *string `json:"quiet_hours_end"`
+	Timezone        *string `json:"timezone"`
+}
+
+func (r NotificationSettingsRequest) Validate() error {
+	if (r.QuietHoursStart == nil) != (r.QuietHoursEnd == nil) {
+		return errors.New("quiet hours start and end must be set together")
+	}
+
+	if r.QuietHoursStart != nil && (*r.QuietHoursStart == "") != (*r.QuietHoursEnd == "") {
+		return errors.New("quiet hours start and end must both be empty to turn quiet hours off")
+	}
+
+	for _, value := range []*string{r.QuietHoursStart, r.QuietHoursEnd} {
+		if value == nil || *value == "" {
+			continue
+		}
+		if _, err := db.ParseQuietHour(*value); err != nil {
+			return err
+		}
+	}
+
+	if r.Timezone != nil && *r.Timezone != "" {
+		if _, err := time.LoadLocation(*r.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone %q", *r.Timezone)
+		}
+	}
+
+	return nil
+}
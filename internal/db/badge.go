@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+)
+
+// Badge IDs, seeded by migrations/3_badges.sql
+const (
+	BadgeFirstExactScore = "first_exact_score"
+	BadgeWinStreak5      = "win_streak_5"
+	BadgeWinStreak10     = "win_streak_10"
+	BadgeWinStreak20     = "win_streak_20"
+	BadgePredictions100  = "predictions_100"
+	BadgeSeasonTop10     = "season_top_10"
+	BadgeFullMatchday    = "full_matchday"
+)
+
+// Targets of the badge rules, shared by the awarding engine and the achievements API
+var BadgeWinStreakLengths = map[string]int{
+	BadgeWinStreak5:  5,
+	BadgeWinStreak10: 10,
+	BadgeWinStreak20: 20,
+}
+
+const (
+	BadgePredictionsTarget = 100
+	BadgeSeasonTopPosition = 10
+	// MinMatchdayMatches keeps a round of one or two games from counting as a full matchday
+	MinMatchdayMatches = 3
+)
+
+// AwardBadge gives the badge to the user. It reports false if the user
+// already had it, so callers can notify only on the first award.
+func (s *Storage) AwardBadge(ctx context.Context, userID, badgeID string) (bool, error) {
+	query := `
+		INSERT INTO user_badges (user_id, badge_id, awarded_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id, badge_id) DO NOTHING`
+
+	res, err := s.db.ExecContext(ctx, query, userID, badgeID, s.now())
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (s *Storage) GetBadgeByID(ctx context.Context, id string) (Badge, error) {
+	query := `
+		SELECT id, name, COALESCE(color, ''), COALESCE(icon, ''), COALESCE(description, '')
+		FROM badges
+		WHERE id = ?`
+
+	var badge Badge
+	err := s.db.QueryRowContext(ctx, query, id).Scan(
+		&badge.ID,
+		&badge.Name,
+		&badge.Color,
+		&badge.Icon,
+		&badge.Description,
+	)
+
+	if err != nil && IsNoRowsError(err) {
+		return Badge{}, ErrNotFound
+	} else if err != nil {
+		return Badge{}, err
+	}
+
+	return badge, nil
+}
+
+// CountMatchdayPredictions returns how many matches the matchday of the competition's season has,
+// whatever days they are played on, and how many of them the user has predicted.
+// Matches stored before seasons were recorded have an empty season and count as one.
+func (s *Storage) CountMatchdayPredictions(ctx context.Context, userID, competitionCode, season string, matchday int) (total int, predicted int, err error) {
+	query := `
+		SELECT COUNT(*), COUNT(p.user_id)
+		FROM matches m
+		LEFT JOIN predictions p ON p.match_id = m.id AND p.user_id = ?
+		WHERE m.competition_code = ? AND m.competition_season IS ? AND m.matchday = ?`
+
+	err = s.db.QueryRowContext(ctx, query, userID, competitionCode, nullIfEmpty(season), matchday).Scan(&total, &predicted)
+	return total, predicted, err
+}
+
+func (s *Storage) ListBadges(ctx context.Context) ([]Badge, error) {
+	query := `
+		SELECT id, name, COALESCE(color, ''), COALESCE(icon, ''), COALESCE(description, '')
+		FROM badges
+		ORDER BY rowid`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	badges := make([]Badge, 0)
+	for rows.Next() {
+		var badge Badge
+		if err := rows.Scan(
+			&badge.ID,
+			&badge.Name,
+			&badge.Color,
+			&badge.Icon,
+			&badge.Description,
+		); err != nil {
+			return nil, err
+		}
+		badges = append(badges, badge)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return badges, nil
+}
+
+// CountExactScorePredictions returns how many settled predictions of the user hit the exact score.
+func (s *Storage) CountExactScorePredictions(ctx context.Context, userID string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM predictions p
+		JOIN matches m ON p.match_id = m.id
+		WHERE p.user_id = ?
+		AND p.completed_at IS NOT NULL
+		AND p.predicted_home_score = m.home_score
+		AND p.predicted_away_score = m.away_score`
+
+	var count int
+	err := s.db.QueryRowContext(ctx, query, userID).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,207 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type Comment struct {
+	ID        string          `db:"id" json:"id"`
+	MatchID   string          `db:"match_id" json:"match_id"`
+	UserID    string          `db:"user_id" json:"user_id"`
+	Body      string          `db:"body" json:"body"`
+	IsSpoiler bool            `db:"is_spoiler" json:"is_spoiler"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	Author    User            `db:"-" json:"-"`
+	Reactions []ReactionCount `db:"-" json:"reactions"`
+}
+
+type ReactionCount struct {
+	Emoji   string `json:"emoji"`
+	Count   int    `json:"count"`
+	Reacted bool   `json:"reacted"` // Whether the viewer left this reaction
+}
+
+// CommentReportsToHide is how many reports take a comment out of the thread
+const CommentReportsToHide = 3
+
+func (s *Storage) SaveComment(ctx context.Context, comment Comment) error {
+	query := `
+		INSERT INTO match_comments (id, match_id, user_id, body, is_spoiler, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	// Stored in the cursor format, pagination and the rate limit compare it as text
+	_, err := s.db.ExecContext(ctx, query, comment.ID, comment.MatchID, comment.UserID, comment.Body, comment.IsSpoiler,
+		s.now().Format(cursorTimeFormat))
+	return err
+}
+
+// ListMatchComments returns the match thread newest first, with reactions as seen by viewerID.
+// Comments reported by several users are left out.
+func (s *Storage) ListMatchComments(ctx context.Context, matchID, viewerID string, cursor Cursor, limit int) ([]Comment, error) {
+	query := `
+		SELECT
+			c.id,
+			c.match_id,
+			c.user_id,
+			c.body,
+			c.is_spoiler,
+			c.created_at,
+			u.username,
+			u.first_name,
+			u.last_name,
+			u.avatar_url,
+			(
+				SELECT json_group_array(json_object(
+					'emoji', r.emoji,
+					'count', r.count,
+					'reacted', CASE WHEN r.reacted THEN json('true') ELSE json('false') END
+				))
+				FROM (
+					SELECT emoji, COUNT(*) AS count, MAX(user_id = ?) AS reacted
+					FROM comment_reactions
+					WHERE comment_id = c.id
+					GROUP BY emoji
+				) r
+			) AS reactions
+		FROM match_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.match_id = ?
+		AND (SELECT COUNT(*) FROM comment_reports cr WHERE cr.comment_id = c.id) < ?
+		AND (? = '' OR (c.created_at, c.id) < (?, ?))
+		ORDER BY c.created_at DESC, c.id DESC
+		LIMIT ?`
+
+	args := []interface{}{viewerID, matchID, CommentReportsToHide}
+	args = append(args, cursor.args()...)
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := make([]Comment, 0)
+	for rows.Next() {
+		var comment Comment
+		var reactions interface{}
+		if err := rows.Scan(
+			&comment.ID,
+			&comment.MatchID,
+			&comment.UserID,
+			&comment.Body,
+			&comment.IsSpoiler,
+			&comment.CreatedAt,
+			&comment.Author.Username,
+			&comment.Author.FirstName,
+			&comment.Author.LastName,
+			&comment.Author.AvatarURL,
+			&reactions,
+		); err != nil {
+			return nil, err
+		}
+
+		comment.Author.ID = comment.UserID
+		comment.Reactions, err = UnmarshalJSONToSlice[ReactionCount](reactions)
+		if err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (s *Storage) GetCommentByID(ctx context.Context, id string) (Comment, error) {
+	query := `
+		SELECT id, match_id, user_id, body, is_spoiler, created_at
+		FROM match_comments
+		WHERE id = ?`
+
+	var comment Comment
+	err := s.db.QueryRowContext(ctx, query, id).Scan(
+		&comment.ID,
+		&comment.MatchID,
+		&comment.UserID,
+		&comment.Body,
+		&comment.IsSpoiler,
+		&comment.CreatedAt,
+	)
+
+	if err != nil && IsNoRowsError(err) {
+		return Comment{}, ErrNotFound
+	} else if err != nil {
+		return Comment{}, err
+	}
+
+	return comment, nil
+}
+
+// CountUserCommentsSince is used to rate limit how often a user can comment
+func (s *Storage) CountUserCommentsSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM match_comments
+		WHERE user_id = ? AND created_at >= ?`
+
+	var count int
+	err := s.db.QueryRowContext(ctx, query, userID, since.UTC().Format(cursorTimeFormat)).Scan(&count)
+	return count, err
+}
+
+func (s *Storage) AddCommentReaction(ctx context.Context, commentID, userID, emoji string) error {
+	query := `
+		INSERT INTO comment_reactions (comment_id, user_id, emoji, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (comment_id, user_id, emoji) DO NOTHING`
+
+	_, err := s.db.ExecContext(ctx, query, commentID, userID, emoji, s.now())
+	return err
+}
+
+func (s *Storage) RemoveCommentReaction(ctx context.Context, commentID, userID, emoji string) error {
+	query := `
+		DELETE FROM comment_reactions
+		WHERE comment_id = ? AND user_id = ? AND emoji = ?`
+
+	_, err := s.db.ExecContext(ctx, query, commentID, userID, emoji)
+	return err
+}
+
+func (s *Storage) ReportComment(ctx context.Context, commentID, userID, reason string) error {
+	query := `
+		INSERT INTO comment_reports (comment_id, user_id, reason, created_at)
+		VALUES (?, ?, ?, ?)`
+
+	_, err := s.db.ExecContext(ctx, query, commentID, userID, reason, s.now())
+	if err != nil && IsUniqueViolationError(err) {
+		return ErrAlreadyExists
+	}
+	return err
+}
+
+// GetMatchCommentsVersion returns a value that changes whenever the match thread does.
+// It backs the ETag clients poll with.
+func (s *Storage) GetMatchCommentsVersion(ctx context.Context, matchID string) (string, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) || ':' || COALESCE(MAX(created_at), '') FROM match_comments WHERE match_id = ?)
+			|| '/' ||
+			(SELECT COUNT(*) || ':' || COALESCE(MAX(r.created_at), '')
+			 FROM comment_reactions r JOIN match_comments c ON c.id = r.comment_id
+			 WHERE c.match_id = ?)
+			|| '/' ||
+			(SELECT COUNT(*)
+			 FROM comment_reports cr JOIN match_comments c ON c.id = cr.comment_id
+			 WHERE c.match_id = ?)`
+
+	var version string
+	err := s.db.QueryRowContext(ctx, query, matchID, matchID, matchID).Scan(&version)
+	return version, err
+}
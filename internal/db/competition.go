@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type Competition struct {
+	Code         string    `db:"code" json:"code"`
+	Name         string    `db:"name" json:"name"`
+	Emblem       *string   `db:"emblem" json:"emblem"`
+	Area         *string   `db:"area" json:"area"`
+	Enabled      bool      `db:"enabled" json:"enabled"`
+	SyncPriority int       `db:"sync_priority" json:"sync_priority"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+
+	SyncCursor      *time.Time `db:"sync_cursor" json:"sync_cursor"` // Latest lastUpdated of the provider's matches already stored
+	LastSyncedAt    *time.Time `db:"last_synced_at" json:"last_synced_at"`
+	LastFullSyncAt  *time.Time `db:"last_full_sync_at" json:"last_full_sync_at"`
+	LastSyncError   *string    `db:"last_sync_error" json:"last_sync_error"` // Cleared by the next successful sync
+	LastSyncErrorAt *time.Time `db:"last_sync_error_at" json:"last_sync_error_at"`
+}
+
+// SeedCompetitions adds competitions that are not known yet, leaving existing ones as admins left them
+func (s *Storage) SeedCompetitions(ctx context.Context, competitions []Competition) error {
+	query := `
+		INSERT INTO competitions (code, name, emblem, area, enabled, sync_priority, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (code) DO NOTHING`
+
+	now := s.now()
+	for _, c := range competitions {
+		if _, err := s.db.ExecContext(ctx, query, c.Code, c.Name, c.Emblem, c.Area, c.Enabled, c.SyncPriority, now, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Storage) SaveCompetition(ctx context.Context, c Competition) error {
+	query := `
+		INSERT INTO competitions (code, name, emblem, area, enabled, sync_priority, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (code) DO UPDATE SET
+			name = excluded.name,
+			emblem = excluded.emblem,
+			area = excluded.area,
+			enabled = excluded.enabled,
+			sync_priority = excluded.sync_priority,
+			updated_at = excluded.updated_at`
+
+	now := s.now()
+	_, err := s.db.ExecContext(ctx, query, c.Code, c.Name, c.Emblem, c.Area, c.Enabled, c.SyncPriority, now, now)
+	return err
+}
+
+func (s *Storage) GetCompetitionByCode(ctx context.Context, code string) (Competition, error) {
+	competitions, err := s.listCompetitions(ctx, "WHERE code = ?", code)
+	if err != nil {
+		return Competition{}, err
+	}
+
+	if len(competitions) == 0 {
+		return Competition{}, ErrNotFound
+	}
+
+	return competitions[0], nil
+}
+
+func (s *Storage) ListCompetitions(ctx context.Context) ([]Competition, error) {
+	return s.listCompetitions(ctx, "")
+}
+
+// ListEnabledCompetitions returns competitions to sync, highest priority first
+func (s *Storage) ListEnabledCompetitions(ctx context.Context) ([]Competition, error) {
+	return s.listCompetitions(ctx, "WHERE enabled = 1")
+}
+
+func (s *Storage) listCompetitions(ctx context.Context, condition string, args ...interface{}) ([]Competition, error) {
+	query := `
+		SELECT
+			code,
+			name,
+			emblem,
+			area,
+			enabled,
+			sync_priority,
+			created_at,
+			updated_at,
+			sync_cursor,
+			last_synced_at,
+			last_full_sync_at,
+			last_sync_error,
+			last_sync_error_at
+		FROM competitions
+		` + condition + `
+		ORDER BY sync_priority DESC, code`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	competitions := make([]Competition, 0)
+	for rows.Next() {
+		var c Competition
+		if err := rows.Scan(
+			&c.Code,
+			&c.Name,
+			&c.Emblem,
+			&c.Area,
+			&c.Enabled,
+			&c.SyncPriority,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+			&c.SyncCursor,
+			&c.LastSyncedAt,
+			&c.LastFullSyncAt,
+			&c.LastSyncError,
+			&c.LastSyncErrorAt,
+		); err != nil {
+			return nil, err
+		}
+		competitions = append(competitions, c)
+	}
+
+	return competitions, rows.Err()
+}
+
+// UpdateCompetitionSync records a finished match sync. The cursor only moves forward, nil leaves it as is.
+func (s *Storage) UpdateCompetitionSync(ctx context.Context, code string, cursor *time.Time, syncedAt time.Time, full bool) error {
+	query := `
+		UPDATE competitions
+		SET sync_cursor = CASE
+				WHEN ? IS NULL THEN sync_cursor
+				WHEN sync_cursor IS NULL OR datetime(sync_cursor) < datetime(?) THEN ?
+				ELSE sync_cursor
+			END,
+			last_synced_at = ?,
+			last_full_sync_at = CASE WHEN ? THEN ? ELSE last_full_sync_at END,
+			last_sync_error = NULL
+		WHERE code = ?`
+
+	var c interface{}
+	if cursor != nil {
+		c = cursor.UTC()
+	}
+
+	syncedAt = syncedAt.UTC()
+	_, err := s.db.ExecContext(ctx, query, c, c, c, syncedAt, full, syncedAt, code)
+	return err
+}
+
+// RecordCompetitionSyncError keeps the reason the competition's last match sync failed
+func (s *Storage) RecordCompetitionSyncError(ctx context.Context, code, syncErr string, at time.Time) error {
+	query := `UPDATE competitions SET last_sync_error = ?, last_sync_error_at = ? WHERE code = ?`
+
+	_, err := s.db.ExecContext(ctx, query, syncErr, at.UTC(), code)
+	return err
+}
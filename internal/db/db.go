@@ -6,11 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"github.com/mattn/go-sqlite3"
+	"github.com/user/project/internal/clock"
 	"time"
 )
 
 type Storage struct {
-	db *sql.DB
+	db    *sql.DB
+	clock clock.Clock
 }
 
 func (s *Storage) AddPrediction(ctx context.Context, prediction Prediction) error {
@@ -62,20 +64,53 @@ func ConnectDB(dbPath string) (*Storage, error) {
 		return nil, err
 	}
 
-	return &Storage{db: db}, nil
+	return &Storage{db: db, clock: clock.Real()}, nil
 }
 
 func NewStorage(db *sql.DB) *Storage {
 	return &Storage{
-		db: db,
+		db:    db,
+		clock: clock.Real(),
 	}
 }
 
+// SetClock replaces the clock used for timestamps the storage writes itself,
+// like prediction settlement times, so they follow a simulated clock
+func (s *Storage) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+func (s *Storage) now() time.Time {
+	return s.clock.Now().UTC()
+}
+
 var (
 	ErrNotFound      = errors.New("not found")
 	ErrAlreadyExists = errors.New("already exists")
+
+	// ErrCompetitionRequired is returned when matches are filtered by matchday or stage without a competition
+	ErrCompetitionRequired = errors.New("competition is required to filter by matchday or stage")
 )
 
+// Cursor points at the last item of a page in lists ordered by time and ID, newest first.
+// The zero value starts from the newest item.
+type Cursor struct {
+	Time time.Time
+	ID   string
+}
+
+// cursorTimeFormat is what SQLite's datetime() returns, used to compare times stored in different formats
+const cursorTimeFormat = "2006-01-02 15:04:05"
+
+// args returns the three values bound by the cursor condition; an empty cursor matches everything
+func (c Cursor) args() []interface{} {
+	var t string
+	if c.ID != "" {
+		t = c.Time.UTC().Format(cursorTimeFormat)
+	}
+	return []interface{}{c.ID, t, c.ID}
+}
+
 type HealthStats struct {
 	Status            string `json:"status"`
 	Error             string `json:"error,omitempty"`
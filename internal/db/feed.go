@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	FeedEventPredictionPlaced  = "prediction_placed"
+	FeedEventPredictionSettled = "prediction_settled"
+	FeedEventBadgeEarned       = "badge_earned"
+	FeedEventSeasonPlacement   = "season_placement"
+)
+
+// FeedEvent is a single thing a followed user did. RelatedID points to a match,
+// badge or season depending on Type.
+type FeedEvent struct {
+	ID         string    `db:"id"`
+	Type       string    `db:"type"`
+	UserID     string    `db:"user_id"`
+	RelatedID  string    `db:"related_id"`
+	OccurredAt time.Time `db:"occurred_at"`
+	Points     *int      `db:"points"`
+	Position   *int      `db:"position"`
+}
+
+// GetFeed returns events of the users the given user follows, newest first.
+// Users with a private profile are left out.
+func (s *Storage) GetFeed(ctx context.Context, userID string, cursor Cursor, limit int) ([]FeedEvent, error) {
+	query := `
+		WITH followed AS (
+			SELECT u.id
+			FROM user_followers uf
+			JOIN users u ON u.id = uf.following_id
+			WHERE uf.follower_id = ? AND COALESCE(u.privacy, 'public') != 'private'
+		), placements AS (
+			SELECT
+				l.user_id,
+				l.season_id,
+				l.points,
+				RANK() OVER (PARTITION BY l.season_id ORDER BY l.points DESC) AS position,
+				datetime(s.end_date, '+1 day') AS occurred_at
+			FROM leaderboards l
+			JOIN seasons s ON s.id = l.season_id
+			WHERE s.is_active = 0
+		), events AS (
+			SELECT 'placed:' || p.user_id || ':' || p.match_id AS id, 'prediction_placed' AS type, p.user_id, p.match_id AS related_id,
+			       datetime(p.created_at) AS occurred_at, NULL AS points, NULL AS position
+			FROM predictions p
+			JOIN followed f ON f.id = p.user_id
+			UNION ALL
+			SELECT 'settled:' || p.user_id || ':' || p.match_id, 'prediction_settled', p.user_id, p.match_id,
+			       datetime(p.completed_at), p.points_awarded, NULL
+			FROM predictions p
+			JOIN followed f ON f.id = p.user_id
+			WHERE p.completed_at IS NOT NULL
+			UNION ALL
+			SELECT 'badge:' || ub.user_id || ':' || ub.badge_id, 'badge_earned', ub.user_id, ub.badge_id,
+			       datetime(ub.awarded_at), NULL, NULL
+			FROM user_badges ub
+			JOIN followed f ON f.id = ub.user_id
+			UNION ALL
+			SELECT 'season:' || pl.user_id || ':' || pl.season_id, 'season_placement', pl.user_id, pl.season_id,
+			       pl.occurred_at, pl.points, pl.position
+			FROM placements pl
+			JOIN followed f ON f.id = pl.user_id
+		)
+		SELECT id, type, user_id, related_id, occurred_at, points, position
+		FROM events
+		WHERE ? = '' OR (occurred_at, id) < (?, ?)
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT ?`
+
+	args := append([]interface{}{userID}, cursor.args()...)
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]FeedEvent, 0)
+	for rows.Next() {
+		var event FeedEvent
+		var occurredAt string
+		if err := rows.Scan(
+			&event.ID,
+			&event.Type,
+			&event.UserID,
+			&event.RelatedID,
+			&occurredAt,
+			&event.Points,
+			&event.Position,
+		); err != nil {
+			return nil, err
+		}
+
+		event.OccurredAt, err = time.Parse(cursorTimeFormat, occurredAt)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	JobRunStatusRunning   = "running"
+	JobRunStatusSucceeded = "succeeded"
+	JobRunStatusFailed    = "failed"
+)
+
+// JobRun is one execution of a background job
+type JobRun struct {
+	ID                 string     `db:"id" json:"id"`
+	Job                string     `db:"job" json:"job"`
+	Status             string     `db:"status" json:"status"`
+	StartedAt          time.Time  `db:"started_at" json:"started_at"`
+	FinishedAt         *time.Time `db:"finished_at" json:"finished_at"`
+	MatchesUpserted    int        `db:"matches_upserted" json:"matches_upserted"`
+	PredictionsSettled int        `db:"predictions_settled" json:"predictions_settled"`
+	Error              *string    `db:"error" json:"error"`
+}
+
+func (s *Storage) StartJobRun(ctx context.Context, run JobRun) error {
+	query := `INSERT INTO job_runs (id, job, status, started_at) VALUES (?, ?, ?, ?)`
+
+	_, err := s.db.ExecContext(ctx, query, run.ID, run.Job, JobRunStatusRunning, run.StartedAt.UTC())
+	return err
+}
+
+// FinishJobRun stores the outcome and counters of a run started with StartJobRun
+func (s *Storage) FinishJobRun(ctx context.Context, run JobRun) error {
+	query := `
+		UPDATE job_runs
+		SET status = ?, finished_at = ?, matches_upserted = ?, predictions_settled = ?, error = ?
+		WHERE id = ?`
+
+	var finishedAt interface{}
+	if run.FinishedAt != nil {
+		finishedAt = run.FinishedAt.UTC()
+	}
+
+	_, err := s.db.ExecContext(ctx, query,
+		run.Status, finishedAt, run.MatchesUpserted, run.PredictionsSettled, run.Error, run.ID)
+	return err
+}
+
+// ListJobRuns returns the latest runs, of a single job when job is not empty
+func (s *Storage) ListJobRuns(ctx context.Context, job string, limit int) ([]JobRun, error) {
+	query := `
+		SELECT id, job, status, started_at, finished_at, matches_upserted, predictions_settled, error
+		FROM job_runs
+		WHERE ? = '' OR job = ?
+		ORDER BY started_at DESC
+		LIMIT ?`
+
+	rows, err := s.db.QueryContext(ctx, query, job, job, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := make([]JobRun, 0)
+	for rows.Next() {
+		var run JobRun
+		if err := rows.Scan(
+			&run.ID,
+			&run.Job,
+			&run.Status,
+			&run.StartedAt,
+			&run.FinishedAt,
+			&run.MatchesUpserted,
+			&run.PredictionsSettled,
+			&run.Error,
+		); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// DeleteJobRunsBefore prunes history older than the given time
+func (s *Storage) DeleteJobRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM job_runs WHERE datetime(started_at) < datetime(?)", before.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -4,19 +4,29 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 )
 
 func (s *Storage) GetLeaderboard(ctx context.Context, seasonID string) ([]LeaderboardEntry, error) {
 	query := `
+        WITH last_snapshot AS (
+            SELECT user_id, position
+            FROM leaderboard_snapshots
+            WHERE season_id = ?
+            AND snapshot_date = (SELECT MAX(snapshot_date) FROM leaderboard_snapshots WHERE season_id = ?)
+        )
         SELECT
-            season_id,
-            user_id,
-            points
-        FROM leaderboards
-        WHERE season_id = ?
-        ORDER BY points DESC LIMIT 100`
-
-	rows, err := s.db.QueryContext(ctx, query, seasonID)
+            l.season_id,
+            l.user_id,
+            l.points,
+            RANK() OVER (ORDER BY l.points DESC) AS position,
+            ls.position AS previous_position
+        FROM leaderboards l
+        LEFT JOIN last_snapshot ls ON ls.user_id = l.user_id
+        WHERE l.season_id = ?
+        ORDER BY l.points DESC LIMIT 100`
+
+	rows, err := s.db.QueryContext(ctx, query, seasonID, seasonID, seasonID)
 	if err != nil {
 		return nil, err
 	}
@@ -25,7 +35,7 @@ func (s *Storage) GetLeaderboard(ctx context.Context, seasonID string) ([]Leader
 	var leaderboard []LeaderboardEntry
 	for rows.Next() {
 		var entry LeaderboardEntry
-		if err := rows.Scan(&entry.SeasonID, &entry.UserID, &entry.Points); err != nil {
+		if err := rows.Scan(&entry.SeasonID, &entry.UserID, &entry.Points, &entry.Position, &entry.PreviousPosition); err != nil {
 			return nil, err
 		}
 		leaderboard = append(leaderboard, entry)
@@ -127,3 +137,63 @@ func (s *Storage) GetUserMonthlyRank(ctx context.Context, userID string) (positi
 
 	return position, points, nil
 }
+
+type RankSnapshot struct {
+	SeasonID string    `db:"season_id" json:"season_id"`
+	Date     time.Time `db:"snapshot_date" json:"date"`
+	Position int       `db:"position" json:"position"`
+	Points   int       `db:"points" json:"points"`
+}
+
+// SnapshotLeaderboard records every user's rank in the season for the given day.
+// The first snapshot of a day wins, so re-running the job is a no-op.
+func (s *Storage) SnapshotLeaderboard(ctx context.Context, seasonID string, day time.Time) error {
+	query := `
+		INSERT INTO leaderboard_snapshots (user_id, season_id, snapshot_date, position, points)
+		SELECT
+			user_id,
+			season_id,
+			?,
+			RANK() OVER (ORDER BY points DESC),
+			points
+		FROM leaderboards
+		WHERE season_id = ?
+		ON CONFLICT (user_id, season_id, snapshot_date) DO NOTHING`
+
+	_, err := s.db.ExecContext(ctx, query, day.Format("2006-01-02"), seasonID)
+	return err
+}
+
+func (s *Storage) GetUserRankHistory(ctx context.Context, userID, seasonID string) ([]RankSnapshot, error) {
+	query := `
+		SELECT season_id, snapshot_date, position, points
+		FROM leaderboard_snapshots
+		WHERE user_id = ? AND season_id = ?
+		ORDER BY snapshot_date ASC`
+
+	rows, err := s.db.QueryContext(ctx, query, userID, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]RankSnapshot, 0)
+	for rows.Next() {
+		var snapshot RankSnapshot
+		if err := rows.Scan(
+			&snapshot.SeasonID,
+			&snapshot.Date,
+			&snapshot.Position,
+			&snapshot.Points,
+		); err != nil {
+			return nil, err
+		}
+		history = append(history, snapshot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
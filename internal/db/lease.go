@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// AcquireLease takes or extends the named lease for holder until now+ttl.
+// It fails without error while another holder's lease hasn't expired.
+func (s *Storage) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration, now time.Time) (bool, error) {
+	query := `
+		INSERT INTO job_leases (name, holder, expires_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			holder = excluded.holder,
+			expires_at = excluded.expires_at
+		WHERE job_leases.holder = excluded.holder OR datetime(job_leases.expires_at) < datetime(?)`
+
+	now = now.UTC()
+	res, err := s.db.ExecContext(ctx, query, name, holder, now.Add(ttl), now)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// ReleaseLease gives the lease up so another holder can take it right away
+func (s *Storage) ReleaseLease(ctx context.Context, name, holder string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM job_leases WHERE name = ? AND holder = ?", name, holder)
+	return err
+}
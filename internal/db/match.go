@@ -24,6 +24,60 @@ type Match struct {
 	AwayTeam   Team        `db:"-" json:"away_team"`
 	Prediction *Prediction `db:"-" json:"prediction,omitempty"`
 	Popularity float64     `db:"popularity" json:"popularity"`
+
+	CompetitionCode   string    `db:"competition_code" json:"competition_code"`
+	CompetitionSeason string    `db:"competition_season" json:"competition_season"` // Provider's season ID, matchdays restart every season
+	CompetitionEmblem string    `db:"competition_emblem" json:"competition_emblem"`
+	AreaCode          string    `db:"area_code" json:"area_code"`
+	AreaName          string    `db:"area_name" json:"area_name"`
+	Matchday          *int      `db:"matchday" json:"matchday"` // Nil for competitions without matchdays
+	Stage             string    `db:"stage" json:"stage"`
+	Group             *string   `db:"group_name" json:"group"`
+	Referees          []Referee `db:"referees" json:"referees"`
+	HomeTeamPosition  *int      `db:"-" json:"home_team_position"` // League table position, nil without standings
+	AwayTeamPosition  *int      `db:"-" json:"away_team_position"`
+}
+
+type Referee struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Nationality string `json:"nationality"`
+}
+
+// matchMetadataColumns are selected by queries returning full matches, scanned into metadataDest
+const matchMetadataColumns = `
+	COALESCE(m.competition_code, ''),
+	COALESCE(m.competition_season, ''),
+	COALESCE(m.competition_emblem, ''),
+	COALESCE(m.area_code, ''),
+	COALESCE(m.area_name, ''),
+	m.matchday,
+	COALESCE(m.stage, ''),
+	m.group_name,
+	COALESCE(m.referees, '[]'),
+	(SELECT MIN(st.position) FROM standings st WHERE st.competition_code = m.competition_code AND st.team_id = m.home_team_id),
+	(SELECT MIN(st.position) FROM standings st WHERE st.competition_code = m.competition_code AND st.team_id = m.away_team_id)`
+
+func (m *Match) metadataDest(referees *string) []interface{} {
+	return []interface{}{
+		&m.CompetitionCode,
+		&m.CompetitionSeason,
+		&m.CompetitionEmblem,
+		&m.AreaCode,
+		&m.AreaName,
+		&m.Matchday,
+		&m.Stage,
+		&m.Group,
+		referees,
+		&m.HomeTeamPosition,
+		&m.AwayTeamPosition,
+	}
+}
+
+func (m *Match) decodeReferees(referees string) error {
+	var err error
+	m.Referees, err = UnmarshalJSONToSlice[Referee](referees)
+	return err
 }
 
 const (
@@ -55,26 +109,67 @@ func UnmarshalJSONToStruct[T any](src interface{}) (T, error) {
 	return result, nil
 }
 
-func (s *Storage) GetLastMatchesByTeamID(ctx context.Context, teamID string, limit int) ([]Match, error) {
-	query := `
+// completedMatchesQuery wraps a UNION of per-direction lookups, so each branch reads its latest
+// matches straight off its own (team, date) index instead of scanning on an OR of home and away teams.
+// match_date is compared and ordered raw, datetime() around it would hide it from the index.
+const completedMatchesQuery = `
         SELECT m.id, m.tournament, m.home_team_id, m.away_team_id, m.match_date, m.status, m.home_score, m.away_score, m.popularity,
                json_object('id', home_team.id, 'name', home_team.name, 'short_name', home_team.short_name, 'crest_url', home_team.crest_url, 'country', home_team.country, 'abbreviation', home_team.abbreviation) as home_team,
                json_object('id', away_team.id, 'name', away_team.name, 'short_name', away_team.short_name, 'crest_url', away_team.crest_url, 'country', away_team.country, 'abbreviation', away_team.abbreviation) as away_team
-        FROM matches m
-        JOIN teams home_team ON home_team.id = home_team_id
-        JOIN teams away_team ON away_team.id = away_team_id
-        WHERE (home_team_id = ? OR away_team_id = ?)
-        AND status = ?
-        ORDER BY match_date DESC
+        FROM (%s) m
+        JOIN teams home_team ON home_team.id = m.home_team_id
+        JOIN teams away_team ON away_team.id = m.away_team_id
+        ORDER BY m.match_date DESC
         LIMIT ?
     `
-	rows, err := s.db.QueryContext(ctx, query, teamID, teamID, MatchStatusCompleted, limit)
+
+// latestCompletedMatches is one branch of completedMatchesQuery: the latest completed matches
+// matching the condition that kicked off before a time, taking the condition's arguments,
+// then the status, the time and the limit
+func latestCompletedMatches(condition string) string {
+	return `
+            SELECT * FROM (
+                SELECT id, tournament, home_team_id, away_team_id, match_date, status, home_score, away_score, popularity
+                FROM matches
+                WHERE ` + condition + ` AND status = ? AND match_date < ?
+                ORDER BY match_date DESC
+                LIMIT ?
+            )`
+}
+
+// GetLastMatchesByTeamID returns the team's latest completed matches that kicked off before the given time
+func (s *Storage) GetLastMatchesByTeamID(ctx context.Context, teamID string, before time.Time, limit int) ([]Match, error) {
+	query := fmt.Sprintf(completedMatchesQuery,
+		latestCompletedMatches("home_team_id = ?")+" UNION ALL "+latestCompletedMatches("away_team_id = ?"))
+
+	// Dates are stored in UTC, the bound must be too for the text comparison to hold
+	before = before.UTC()
+	return s.queryCompletedMatches(ctx, query,
+		teamID, MatchStatusCompleted, before, limit,
+		teamID, MatchStatusCompleted, before, limit,
+		limit)
+}
+
+// GetHeadToHead returns the latest completed meetings of two teams before the given time, whoever played at home
+func (s *Storage) GetHeadToHead(ctx context.Context, teamID, opponentID string, before time.Time, limit int) ([]Match, error) {
+	query := fmt.Sprintf(completedMatchesQuery,
+		latestCompletedMatches("home_team_id = ? AND away_team_id = ?")+" UNION ALL "+latestCompletedMatches("home_team_id = ? AND away_team_id = ?"))
+
+	before = before.UTC()
+	return s.queryCompletedMatches(ctx, query,
+		teamID, opponentID, MatchStatusCompleted, before, limit,
+		opponentID, teamID, MatchStatusCompleted, before, limit,
+		limit)
+}
+
+func (s *Storage) queryCompletedMatches(ctx context.Context, query string, args ...interface{}) ([]Match, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var matches []Match
+	matches := make([]Match, 0)
 	for rows.Next() {
 		var match Match
 		var homeTeam, awayTeam interface{}
@@ -110,13 +205,17 @@ func (s *Storage) GetLastMatchesByTeamID(ctx context.Context, teamID string, lim
 		matches = append(matches, match)
 	}
 
-	return matches, nil
+	return matches, rows.Err()
 }
 
+// SaveMatch inserts or updates a match. Popularity is only set on insert,
+// afterwards it is maintained by UpdateMatchPopularity from user engagement.
+// Odds that differ from the last recorded ones are added to the match's odds history.
 func (s *Storage) SaveMatch(ctx context.Context, match Match) error {
 	query := `
-        INSERT INTO matches (id, tournament, home_team_id, away_team_id, match_date, status, away_score, home_score, home_odds, draw_odds, away_odds, popularity)
-        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+        INSERT INTO matches (id, tournament, home_team_id, away_team_id, match_date, status, away_score, home_score, home_odds, draw_odds, away_odds, popularity,
+                             competition_code, competition_season, competition_emblem, area_code, area_name, matchday, stage, group_name, referees, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
         ON CONFLICT(id) DO UPDATE SET
         tournament = excluded.tournament,
         home_team_id = excluded.home_team_id,
@@ -128,14 +227,39 @@ func (s *Storage) SaveMatch(ctx context.Context, match Match) error {
         home_odds = excluded.home_odds,
         draw_odds = excluded.draw_odds,
         away_odds = excluded.away_odds,
-        popularity = excluded.popularity`
+        competition_code = excluded.competition_code,
+        competition_season = excluded.competition_season,
+        competition_emblem = excluded.competition_emblem,
+        area_code = excluded.area_code,
+        area_name = excluded.area_name,
+        matchday = excluded.matchday,
+        stage = excluded.stage,
+        group_name = excluded.group_name,
+        referees = excluded.referees,
+        updated_at = excluded.updated_at`
+
+	referees := "[]"
+	if len(match.Referees) > 0 {
+		data, err := json.Marshal(match.Referees)
+		if err != nil {
+			return err
+		}
+		referees = string(data)
+	}
 
-	_, err := s.db.ExecContext(ctx, query,
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := s.now()
+	_, err = tx.ExecContext(ctx, query,
 		match.ID,
 		match.Tournament,
 		match.HomeTeamID,
 		match.AwayTeamID,
-		match.MatchDate,
+		match.MatchDate.UTC(), // Compared as text by the team history queries
 		match.Status,
 		match.AwayScore,
 		match.HomeScore,
@@ -143,14 +267,74 @@ func (s *Storage) SaveMatch(ctx context.Context, match Match) error {
 		match.DrawOdds,
 		match.AwayOdds,
 		match.Popularity,
+		nullIfEmpty(match.CompetitionCode),
+		nullIfEmpty(match.CompetitionSeason),
+		nullIfEmpty(match.CompetitionEmblem),
+		nullIfEmpty(match.AreaCode),
+		nullIfEmpty(match.AreaName),
+		match.Matchday,
+		nullIfEmpty(match.Stage),
+		match.Group,
+		referees,
+		now,
+		now,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if err := recordOddsSnapshot(ctx, tx, match, now); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+type MatchFilter func(*matchFilters)
+
+type matchFilters struct {
+	Competition string
+	Matchday    int
+	Stage       string
+}
+
+func WithCompetition(code string) MatchFilter {
+	return func(f *matchFilters) { f.Competition = code }
+}
+
+func WithMatchday(matchday int) MatchFilter {
+	return func(f *matchFilters) { f.Matchday = matchday }
 }
 
-func (s *Storage) GetActiveMatches(ctx context.Context, userID string) ([]Match, error) {
+func WithStage(stage string) MatchFilter {
+	return func(f *matchFilters) { f.Stage = stage }
+}
+
+// GetActiveMatches returns scheduled matches kicking off within a week after now.
+// Filtering by matchday or stage returns the whole round of the competition's current season instead,
+// whatever its dates and statuses. The current season is the one of the next match to kick off,
+// or of the last one played when the competition has nothing scheduled.
+func (s *Storage) GetActiveMatches(ctx context.Context, userID string, now time.Time, opts ...MatchFilter) ([]Match, error) {
 	var query string
 	var args []interface{}
 
+	filters := matchFilters{}
+	for _, opt := range opts {
+		opt(&filters)
+	}
+
+	round := filters.Matchday != 0 || filters.Stage != ""
+	if round && filters.Competition == "" {
+		return nil, ErrCompetitionRequired
+	}
+
 	query = `
 		SELECT
 			m.id,
@@ -159,6 +343,8 @@ func (s *Storage) GetActiveMatches(ctx context.Context, userID string) ([]Match,
 			m.away_team_id,
 			m.match_date,
 			m.status,
+			m.home_score,
+			m.away_score,
 			m.home_odds,
 			m.draw_odds,
 			m.away_odds,
@@ -173,22 +359,52 @@ func (s *Storage) GetActiveMatches(ctx context.Context, userID string) ([]Match,
 						'predicted_outcome', p.predicted_outcome,
 						'predicted_home_score', p.predicted_home_score,
 						'predicted_away_score', p.predicted_away_score,
+						'home_odds', p.home_odds,
+						'draw_odds', p.draw_odds,
+						'away_odds', p.away_odds,
 						'points_awarded', p.points_awarded,
 						'created_at', CASE WHEN p.created_at IS NOT NULL THEN strftime('%Y-%m-%dT%H:%M:%SZ', p.created_at) ELSE NULL END,
 						'updated_at', CASE WHEN p.updated_at IS NOT NULL THEN strftime('%Y-%m-%dT%H:%M:%SZ', p.updated_at) ELSE NULL END,
 						'completed_at', CASE WHEN p.completed_at IS NOT NULL THEN strftime('%Y-%m-%dT%H:%M:%SZ', p.completed_at) ELSE NULL END
 					)
 				ELSE NULL
-			END as prediction
+			END as prediction,` + matchMetadataColumns + `
 		FROM matches m
 		JOIN teams t1 ON m.home_team_id = t1.id
 		JOIN teams t2 ON m.away_team_id = t2.id
-		LEFT JOIN predictions p ON m.id = p.match_id AND p.user_id = ?
-		WHERE m.status = 'scheduled' AND m.match_date BETWEEN datetime('now') AND datetime('now', '+7 days')
-		ORDER BY m.match_date ASC`
+		LEFT JOIN predictions p ON m.id = p.match_id AND p.user_id = ?`
 
 	args = append(args, userID)
 
+	if round {
+		query += `
+		WHERE m.competition_season IS (
+			SELECT competition_season FROM matches
+			WHERE competition_code = ?
+			ORDER BY match_date < ?, CASE WHEN match_date < ? THEN match_date END DESC, match_date
+			LIMIT 1
+		)`
+		args = append(args, filters.Competition, now.UTC(), now.UTC())
+	} else {
+		query += " WHERE m.status = 'scheduled' AND datetime(m.match_date) BETWEEN datetime(?) AND datetime(?, '+7 days')"
+		args = append(args, now.UTC(), now.UTC())
+	}
+
+	if filters.Competition != "" {
+		query += " AND m.competition_code = ?"
+		args = append(args, filters.Competition)
+	}
+	if filters.Matchday != 0 {
+		query += " AND m.matchday = ?"
+		args = append(args, filters.Matchday)
+	}
+	if filters.Stage != "" {
+		query += " AND m.stage = ?"
+		args = append(args, filters.Stage)
+	}
+
+	query += " ORDER BY m.match_date ASC"
+
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
@@ -199,13 +415,16 @@ func (s *Storage) GetActiveMatches(ctx context.Context, userID string) ([]Match,
 	for rows.Next() {
 		var match Match
 		var homeTeam, awayTeam, prediction interface{}
-		if err := rows.Scan(
+		var referees string
+		dest := []interface{}{
 			&match.ID,
 			&match.Tournament,
 			&match.HomeTeamID,
 			&match.AwayTeamID,
 			&match.MatchDate,
 			&match.Status,
+			&match.HomeScore,
+			&match.AwayScore,
 			&match.HomeOdds,
 			&match.DrawOdds,
 			&match.AwayOdds,
@@ -213,7 +432,13 @@ func (s *Storage) GetActiveMatches(ctx context.Context, userID string) ([]Match,
 			&homeTeam,
 			&awayTeam,
 			&prediction,
-		); err != nil {
+		}
+
+		if err := rows.Scan(append(dest, match.metadataDest(&referees)...)...); err != nil {
+			return nil, err
+		}
+
+		if err := match.decodeReferees(referees); err != nil {
 			return nil, err
 		}
 
@@ -266,7 +491,7 @@ func (s *Storage) GetMatchByID(ctx context.Context, id string) (Match, error) {
 			m.away_odds,
 			m.popularity,
 			json_object('id', t1.id, 'name', t1.name, 'short_name', t1.short_name, 'crest_url', t1.crest_url, 'country', t1.country, 'abbreviation', t1.abbreviation) as home_team,
-			json_object('id', t2.id, 'name', t2.name, 'short_name', t2.short_name, 'crest_url', t2.crest_url, 'country', t2.country, 'abbreviation', t2.abbreviation) as away_team
+			json_object('id', t2.id, 'name', t2.name, 'short_name', t2.short_name, 'crest_url', t2.crest_url, 'country', t2.country, 'abbreviation', t2.abbreviation) as away_team,` + matchMetadataColumns + `
 		FROM matches m
 		JOIN teams t1 ON m.home_team_id = t1.id
 		JOIN teams t2 ON m.away_team_id = t2.id
@@ -274,9 +499,10 @@ func (s *Storage) GetMatchByID(ctx context.Context, id string) (Match, error) {
 
 	var match Match
 	var homeTeam, awayTeam interface{}
+	var referees string
 	row := s.db.QueryRowContext(ctx, query, id)
 
-	if err := row.Scan(
+	dest := []interface{}{
 		&match.ID,
 		&match.Tournament,
 		&match.HomeTeamID,
@@ -291,12 +517,18 @@ func (s *Storage) GetMatchByID(ctx context.Context, id string) (Match, error) {
 		&match.Popularity,
 		&homeTeam,
 		&awayTeam,
-	); err != nil && IsNoRowsError(err) {
+	}
+
+	if err := row.Scan(append(dest, match.metadataDest(&referees)...)...); err != nil && IsNoRowsError(err) {
 		return Match{}, ErrNotFound
 	} else if err != nil {
 		return Match{}, err
 	}
 
+	if err := match.decodeReferees(referees); err != nil {
+		return Match{}, err
+	}
+
 	homeTeamStruct, err := UnmarshalJSONToStruct[Team](homeTeam)
 	if err != nil {
 		return Match{}, err
@@ -315,7 +547,8 @@ func (s *Storage) GetMatchByID(ctx context.Context, id string) (Match, error) {
 
 func (s *Storage) GetCompletedMatchesWithoutCompletedPredictions(ctx context.Context) ([]Match, error) {
 	query := `
-		SELECT m.id, m.home_score, m.away_score
+		SELECT m.id, m.tournament, m.match_date, m.home_score, m.away_score,
+		       COALESCE(m.competition_code, ''), COALESCE(m.competition_season, ''), m.matchday
 		FROM matches m
 		WHERE m.status = 'completed' AND (SELECT COUNT(*) FROM predictions p WHERE p.match_id = m.id AND p.completed_at IS NOT NULL) = 0
 	`
@@ -329,7 +562,7 @@ func (s *Storage) GetCompletedMatchesWithoutCompletedPredictions(ctx context.Con
 	var matches []Match
 	for rows.Next() {
 		var match Match
-		if err := rows.Scan(&match.ID, &match.HomeScore, &match.AwayScore); err != nil {
+		if err := rows.Scan(&match.ID, &match.Tournament, &match.MatchDate, &match.HomeScore, &match.AwayScore, &match.CompetitionCode, &match.CompetitionSeason, &match.Matchday); err != nil {
 			return nil, err
 		}
 		matches = append(matches, match)
@@ -338,16 +571,16 @@ func (s *Storage) GetCompletedMatchesWithoutCompletedPredictions(ctx context.Con
 	return matches, nil
 }
 
-func (s *Storage) GetMatchesForTeam(ctx context.Context, teamID string, hoursAhead int) ([]Match, error) {
+func (s *Storage) GetMatchesForTeam(ctx context.Context, teamID string, from, to time.Time) ([]Match, error) {
 	query := `
         SELECT id, tournament, home_team_id, away_team_id, match_date, status, popularity
         FROM matches
         WHERE (home_team_id = ? OR away_team_id = ?)
         AND status = ?
-        AND match_date BETWEEN DATETIME('now') AND DATETIME('now', ? || ' hours')
+        AND datetime(match_date) BETWEEN datetime(?) AND datetime(?)
     `
 
-	rows, err := s.db.QueryContext(ctx, query, teamID, teamID, MatchStatusScheduled, hoursAhead)
+	rows, err := s.db.QueryContext(ctx, query, teamID, teamID, MatchStatusScheduled, from.UTC(), to.UTC())
 	if err != nil {
 		return nil, err
 	}
@@ -405,7 +638,8 @@ func (s *Storage) GetPredictionStats(ctx context.Context, matchID string) (Predi
 	}, nil
 }
 
-func (s *Storage) GetTodayMostPopularMatch(ctx context.Context) (Match, error) {
+// GetTodayMostPopularMatch returns the most popular match kicking off between from and to.
+func (s *Storage) GetTodayMostPopularMatch(ctx context.Context, from, to time.Time) (Match, error) {
 	query := `
 		SELECT m.id, m.tournament, m.home_team_id, m.away_team_id, m.match_date, m.status, m.home_score, m.away_score, m.popularity,
 			   json_object('id', home_team.id, 'name', home_team.name, 'short_name', home_team.short_name, 'crest_url', home_team.crest_url, 'country', home_team.country, 'abbreviation', home_team.abbreviation) as home_team,
@@ -413,14 +647,14 @@ func (s *Storage) GetTodayMostPopularMatch(ctx context.Context) (Match, error) {
 		FROM matches m
 		JOIN teams home_team ON home_team.id = home_team_id
 		JOIN teams away_team ON away_team.id = away_team_id
-		WHERE datetime(m.match_date) BETWEEN datetime('now', 'localtime') AND datetime('now', '+24 hours', 'localtime')
+		WHERE datetime(m.match_date) BETWEEN datetime(?) AND datetime(?)
 		ORDER BY m.popularity DESC
 		LIMIT 1
 	`
 
 	var match Match
 	var homeTeam, awayTeam interface{}
-	row := s.db.QueryRowContext(ctx, query)
+	row := s.db.QueryRowContext(ctx, query, from.UTC(), to.UTC())
 
 	if err := row.Scan(
 		&match.ID,
@@ -453,3 +687,37 @@ func (s *Storage) GetTodayMostPopularMatch(ctx context.Context) (Match, error) {
 
 	return match, nil
 }
+
+// CountLiveMatches counts matches that are in play or about to be: ongoing ones and scheduled ones
+// kicking off within lead. Matches that started more than overrun ago are ignored, so a result
+// the provider never published doesn't keep polling going forever.
+func (s *Storage) CountLiveMatches(ctx context.Context, now time.Time, lead, overrun time.Duration) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM matches
+		WHERE status IN (?, ?)
+		AND datetime(match_date) BETWEEN datetime(?) AND datetime(?)`
+
+	var count int
+	err := s.db.QueryRowContext(ctx, query,
+		MatchStatusOngoing, MatchStatusScheduled, now.Add(-overrun).UTC(), now.Add(lead).UTC()).Scan(&count)
+	return count, err
+}
+
+// GetNextKickoff returns when the next scheduled match starts
+func (s *Storage) GetNextKickoff(ctx context.Context, now time.Time) (time.Time, error) {
+	query := `
+		SELECT match_date
+		FROM matches
+		WHERE status = ? AND datetime(match_date) > datetime(?)
+		ORDER BY datetime(match_date)
+		LIMIT 1`
+
+	var kickoff time.Time
+	err := s.db.QueryRowContext(ctx, query, MatchStatusScheduled, now.UTC()).Scan(&kickoff)
+	if err != nil && IsNoRowsError(err) {
+		return time.Time{}, ErrNotFound
+	}
+
+	return kickoff, err
+}
@@ -2,9 +2,11 @@ package db
 
 // LeaderboardEntry represents an entry in the leaderboard
 type LeaderboardEntry struct {
-	UserID   string `db:"user_id"`
-	Points   int    `db:"points"`
-	SeasonID string `db:"season_id"`
+	UserID           string `db:"user_id"`
+	Points           int    `db:"points"`
+	SeasonID         string `db:"season_id"`
+	Position         int    `db:"position"`
+	PreviousPosition *int   `db:"previous_position"` // Nil if the user had no snapshot yet
 }
 
 // Team represents a sports team
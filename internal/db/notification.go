@@ -12,10 +12,10 @@ const (
 
 func (s *Storage) LogNotification(ctx context.Context, userID, notificationType, relatedID string) error {
 	query := `
-        INSERT INTO notifications (id, user_id, notification_type, related_id)
-        VALUES (?, ?, ?, ?)
+        INSERT INTO notifications (id, user_id, notification_type, related_id, sent_at)
+        VALUES (?, ?, ?, ?, ?)
     `
-	_, err := s.db.ExecContext(ctx, query, nanoid.Must(), userID, notificationType, relatedID)
+	_, err := s.db.ExecContext(ctx, query, nanoid.Must(), userID, notificationType, relatedID, s.now())
 	return err
 }
 
@@ -49,10 +49,10 @@ func (s *Storage) GetWeeklyRecap(ctx context.Context, userID string) (WeeklyReca
             COALESCE(u.current_win_streak, 0) AS current_streak
         FROM predictions p
         JOIN users u ON p.user_id = u.id
-        WHERE p.user_id = ? AND p.created_at >= DATE('now', '-7 days')
+        WHERE p.user_id = ? AND datetime(p.created_at) >= datetime(?)
     `
 	var recap WeeklyRecap
-	err := s.db.QueryRowContext(ctx, query, userID).Scan(
+	err := s.db.QueryRowContext(ctx, query, userID, s.now().AddDate(0, 0, -7)).Scan(
 		&recap.TotalPredictions,
 		&recap.Wins,
 		&recap.Losses,
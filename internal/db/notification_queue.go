@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/project/internal/nanoid"
+)
+
+// QueuedNotification is a message held back until the user's quiet hours end
+type QueuedNotification struct {
+	ID           string     `db:"id" json:"id"`
+	User         User       `db:"user" json:"user"` // Only the ID, chat and language are loaded
+	Kind         string     `db:"kind" json:"kind"`
+	Message      string     `db:"message" json:"message"`
+	WebAppURL    string     `db:"web_app_url" json:"web_app_url"`
+	ButtonText   string     `db:"button_text" json:"button_text"`
+	Image        []byte     `db:"image" json:"-"`
+	DeliverAfter time.Time  `db:"deliver_after" json:"deliver_after"`
+	ExpiresAt    *time.Time `db:"expires_at" json:"expires_at"` // Dropped instead of sent late
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+}
+
+func (s *Storage) QueueNotification(ctx context.Context, n QueuedNotification) error {
+	query := `
+		INSERT INTO notification_queue (id, user_id, kind, message, web_app_url, button_text, image, deliver_after, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	var expiresAt *time.Time
+	if n.ExpiresAt != nil {
+		utc := n.ExpiresAt.UTC()
+		expiresAt = &utc
+	}
+
+	_, err := s.db.ExecContext(ctx, query,
+		nanoid.Must(),
+		n.User.ID,
+		n.Kind,
+		n.Message,
+		nullIfEmpty(n.WebAppURL),
+		nullIfEmpty(n.ButtonText),
+		n.Image,
+		n.DeliverAfter.UTC(),
+		expiresAt,
+		s.now(),
+	)
+	return err
+}
+
+// GetDueNotifications returns queued notifications whose quiet hours are over, oldest first
+func (s *Storage) GetDueNotifications(ctx context.Context, now time.Time) ([]QueuedNotification, error) {
+	query := `
+		SELECT q.id, u.id, u.chat_id, u.language_code, q.kind, q.message,
+		       COALESCE(q.web_app_url, ''), COALESCE(q.button_text, ''), q.image,
+		       q.deliver_after, q.expires_at, q.created_at
+		FROM notification_queue q
+		JOIN users u ON u.id = q.user_id
+		WHERE q.deliver_after <= ?
+		ORDER BY q.deliver_after, q.created_at`
+
+	rows, err := s.db.QueryContext(ctx, query, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var queued []QueuedNotification
+	for rows.Next() {
+		var n QueuedNotification
+		if err := rows.Scan(
+			&n.ID,
+			&n.User.ID,
+			&n.User.ChatID,
+			&n.User.LanguageCode,
+			&n.Kind,
+			&n.Message,
+			&n.WebAppURL,
+			&n.ButtonText,
+			&n.Image,
+			&n.DeliverAfter,
+			&n.ExpiresAt,
+			&n.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		queued = append(queued, n)
+	}
+
+	return queued, rows.Err()
+}
+
+func (s *Storage) DeleteQueuedNotification(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM notification_queue WHERE id = ?`, id)
+	return err
+}
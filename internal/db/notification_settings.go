@@ -0,0 +1,197 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Kinds of notifications users can turn off
+const (
+	NotificationFavoriteTeam = "favorite_team" // Reminders about the favourite team's matches
+	NotificationReminders    = "reminders"     // Kickoff reminders for matches the user usually predicts
+	NotificationStreaks      = "streaks"       // Prediction streaks and their bonus points
+	NotificationResults      = "results"       // Outcomes of settled predictions and badges they earn
+	NotificationRecaps       = "recaps"        // Weekly recaps
+	NotificationMarketing    = "marketing"     // Broadcasts and announcements
+)
+
+// NotificationSettings are a user's notification preferences. Quiet hours are HH:MM
+// in the user's timezone and may span midnight, e.g. 23:00 to 08:00.
+type NotificationSettings struct {
+	UserID          string    `db:"user_id" json:"user_id"`
+	FavoriteTeam    bool      `db:"favorite_team" json:"favorite_team"`
+	Reminders       bool      `db:"reminders" json:"reminders"`
+	Streaks         bool      `db:"streaks" json:"streaks"`
+	Results         bool      `db:"results" json:"results"`
+	Recaps          bool      `db:"recaps" json:"recaps"`
+	Marketing       bool      `db:"marketing" json:"marketing"`
+	QuietHoursStart *string   `db:"quiet_hours_start" json:"quiet_hours_start"`
+	QuietHoursEnd   *string   `db:"quiet_hours_end" json:"quiet_hours_end"`
+	Timezone        *string   `db:"timezone" json:"timezone"` // IANA name, nil for the league timezone
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+}
+
+const quietHoursFormat = "15:04"
+
+// DefaultNotificationSettings apply to users who never changed their settings: everything on, no quiet hours
+func DefaultNotificationSettings(userID string) NotificationSettings {
+	return NotificationSettings{
+		UserID:       userID,
+		FavoriteTeam: true,
+		Reminders:    true,
+		Streaks:      true,
+		Results:      true,
+		Recaps:       true,
+		Marketing:    true,
+	}
+}
+
+// Enabled reports whether the user wants notifications of the kind
+func (n NotificationSettings) Enabled(kind string) bool {
+	switch kind {
+	case NotificationFavoriteTeam:
+		return n.FavoriteTeam
+	case NotificationReminders:
+		return n.Reminders
+	case NotificationStreaks:
+		return n.Streaks
+	case NotificationResults:
+		return n.Results
+	case NotificationRecaps:
+		return n.Recaps
+	case NotificationMarketing:
+		return n.Marketing
+	default:
+		return true
+	}
+}
+
+// Location returns the user's timezone, or fallback when they haven't set a valid one
+func (n NotificationSettings) Location(fallback *time.Location) *time.Location {
+	if n.Timezone == nil || *n.Timezone == "" {
+		return fallback
+	}
+
+	loc, err := time.LoadLocation(*n.Timezone)
+	if err != nil {
+		return fallback
+	}
+	return loc
+}
+
+// InQuietHours reports whether t falls into the user's quiet hours
+func (n NotificationSettings) InQuietHours(t time.Time, fallback *time.Location) bool {
+	if n.QuietHoursStart == nil || n.QuietHoursEnd == nil {
+		return false
+	}
+
+	start, err := ParseQuietHour(*n.QuietHoursStart)
+	if err != nil {
+		return false
+	}
+	end, err := ParseQuietHour(*n.QuietHoursEnd)
+	if err != nil {
+		return false
+	}
+
+	local := t.In(n.Location(fallback))
+	minute := local.Hour()*60 + local.Minute()
+
+	switch {
+	case start == end:
+		return false
+	case start < end:
+		return minute >= start && minute < end
+	default: // Spans midnight
+		return minute >= start || minute < end
+	}
+}
+
+// QuietUntil returns when the quiet hours t falls into are over, or the zero time outside them
+func (n NotificationSettings) QuietUntil(t time.Time, fallback *time.Location) time.Time {
+	if !n.InQuietHours(t, fallback) {
+		return time.Time{}
+	}
+
+	end, _ := ParseQuietHour(*n.QuietHoursEnd) // InQuietHours has validated it
+	local := t.In(n.Location(fallback))
+	over := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, local.Location())
+	if !over.After(local) {
+		over = over.AddDate(0, 0, 1)
+	}
+	return over
+}
+
+// ParseQuietHour reads an HH:MM time of day as minutes since midnight
+func ParseQuietHour(value string) (int, error) {
+	t, err := time.Parse(quietHoursFormat, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// GetNotificationSettings returns the user's settings, the defaults when they have none stored
+func (s *Storage) GetNotificationSettings(ctx context.Context, userID string) (NotificationSettings, error) {
+	query := `
+		SELECT user_id, favorite_team, reminders, streaks, results, recaps, marketing,
+		       quiet_hours_start, quiet_hours_end, timezone, updated_at
+		FROM notification_settings
+		WHERE user_id = ?`
+
+	var n NotificationSettings
+	err := s.db.QueryRowContext(ctx, query, userID).Scan(
+		&n.UserID,
+		&n.FavoriteTeam,
+		&n.Reminders,
+		&n.Streaks,
+		&n.Results,
+		&n.Recaps,
+		&n.Marketing,
+		&n.QuietHoursStart,
+		&n.QuietHoursEnd,
+		&n.Timezone,
+		&n.UpdatedAt,
+	)
+	if err != nil && IsNoRowsError(err) {
+		return DefaultNotificationSettings(userID), nil
+	} else if err != nil {
+		return NotificationSettings{}, err
+	}
+
+	return n, nil
+}
+
+func (s *Storage) SaveNotificationSettings(ctx context.Context, n NotificationSettings) error {
+	query := `
+		INSERT INTO notification_settings (user_id, favorite_team, reminders, streaks, results, recaps, marketing,
+		                                   quiet_hours_start, quiet_hours_end, timezone, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+			favorite_team = excluded.favorite_team,
+			reminders = excluded.reminders,
+			streaks = excluded.streaks,
+			results = excluded.results,
+			recaps = excluded.recaps,
+			marketing = excluded.marketing,
+			quiet_hours_start = excluded.quiet_hours_start,
+			quiet_hours_end = excluded.quiet_hours_end,
+			timezone = excluded.timezone,
+			updated_at = excluded.updated_at`
+
+	_, err := s.db.ExecContext(ctx, query,
+		n.UserID,
+		n.FavoriteTeam,
+		n.Reminders,
+		n.Streaks,
+		n.Results,
+		n.Recaps,
+		n.Marketing,
+		n.QuietHoursStart,
+		n.QuietHoursEnd,
+		n.Timezone,
+		s.now(),
+	)
+	return err
+}
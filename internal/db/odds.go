@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/user/project/internal/nanoid"
+)
+
+// OddsSnapshot is a match's odds from the moment they last changed
+type OddsSnapshot struct {
+	ID         string    `db:"id" json:"id"`
+	MatchID    string    `db:"match_id" json:"match_id"`
+	HomeOdds   *float64  `db:"home_odds" json:"home_odds"`
+	DrawOdds   *float64  `db:"draw_odds" json:"draw_odds"`
+	AwayOdds   *float64  `db:"away_odds" json:"away_odds"`
+	RecordedAt time.Time `db:"recorded_at" json:"recorded_at"`
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// recordOddsSnapshot adds the match's odds to its history unless they equal the latest
+// snapshot. Matches without any odds are skipped, a provider dropping them isn't a move.
+func recordOddsSnapshot(ctx context.Context, db execer, match Match, at time.Time) error {
+	if match.HomeOdds == nil && match.DrawOdds == nil && match.AwayOdds == nil {
+		return nil
+	}
+
+	query := `
+		INSERT INTO odds_snapshots (id, match_id, home_odds, draw_odds, away_odds, recorded_at)
+		SELECT ?, ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (
+			SELECT 1
+			FROM (
+				SELECT home_odds, draw_odds, away_odds
+				FROM odds_snapshots
+				WHERE match_id = ?
+				ORDER BY recorded_at DESC
+				LIMIT 1
+			) last
+			WHERE last.home_odds IS ? AND last.draw_odds IS ? AND last.away_odds IS ?
+		)`
+
+	_, err := db.ExecContext(ctx, query,
+		nanoid.Must(), match.ID, match.HomeOdds, match.DrawOdds, match.AwayOdds, at,
+		match.ID, match.HomeOdds, match.DrawOdds, match.AwayOdds,
+	)
+	return err
+}
+
+// GetOddsHistory returns the match's odds snapshots, oldest first
+func (s *Storage) GetOddsHistory(ctx context.Context, matchID string) ([]OddsSnapshot, error) {
+	query := `
+		SELECT id, match_id, home_odds, draw_odds, away_odds, recorded_at
+		FROM odds_snapshots
+		WHERE match_id = ?
+		ORDER BY recorded_at`
+
+	rows, err := s.db.QueryContext(ctx, query, matchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := make([]OddsSnapshot, 0)
+	for rows.Next() {
+		var snapshot OddsSnapshot
+		if err := rows.Scan(
+			&snapshot.ID,
+			&snapshot.MatchID,
+			&snapshot.HomeOdds,
+			&snapshot.DrawOdds,
+			&snapshot.AwayOdds,
+			&snapshot.RecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, rows.Err()
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// MatchPopularityInputs holds what popularity is computed from for one upcoming match
+type MatchPopularityInputs struct {
+	MatchID          string    `db:"id"`
+	MatchDate        time.Time `db:"match_date"`
+	HomeOdds         *float64  `db:"home_odds"`
+	AwayOdds         *float64  `db:"away_odds"`
+	HomeTeamTLA      string    `db:"home_tla"`
+	AwayTeamTLA      string    `db:"away_tla"`
+	HomeTeamPosition *int      `db:"home_position"`
+	AwayTeamPosition *int      `db:"away_position"`
+	Predictions      int       `db:"predictions"` // Predictions made on the match so far
+	Fans             int       `db:"fans"`        // Users whose favorite team plays in the match
+}
+
+// GetMatchPopularityInputs returns engagement and table data for scheduled matches kicking off in [from, to]
+func (s *Storage) GetMatchPopularityInputs(ctx context.Context, from, to time.Time) ([]MatchPopularityInputs, error) {
+	query := `
+		SELECT
+			m.id,
+			m.match_date,
+			m.home_odds,
+			m.away_odds,
+			COALESCE(ht.abbreviation, ''),
+			COALESCE(at.abbreviation, ''),
+			(SELECT MIN(st.position) FROM standings st WHERE st.competition_code = m.competition_code AND st.team_id = m.home_team_id),
+			(SELECT MIN(st.position) FROM standings st WHERE st.competition_code = m.competition_code AND st.team_id = m.away_team_id),
+			(SELECT COUNT(*) FROM predictions p WHERE p.match_id = m.id),
+			(SELECT COUNT(*) FROM users u WHERE u.favorite_team_id IN (m.home_team_id, m.away_team_id))
+		FROM matches m
+		JOIN teams ht ON ht.id = m.home_team_id
+		JOIN teams at ON at.id = m.away_team_id
+		WHERE m.status = ?
+		AND datetime(m.match_date) BETWEEN datetime(?) AND datetime(?)`
+
+	rows, err := s.db.QueryContext(ctx, query, MatchStatusScheduled, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	inputs := make([]MatchPopularityInputs, 0)
+	for rows.Next() {
+		var in MatchPopularityInputs
+		if err := rows.Scan(
+			&in.MatchID,
+			&in.MatchDate,
+			&in.HomeOdds,
+			&in.AwayOdds,
+			&in.HomeTeamTLA,
+			&in.AwayTeamTLA,
+			&in.HomeTeamPosition,
+			&in.AwayTeamPosition,
+			&in.Predictions,
+			&in.Fans,
+		); err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, in)
+	}
+
+	return inputs, rows.Err()
+}
+
+// UpdateMatchPopularity stores recomputed scores keyed by match ID
+func (s *Storage) UpdateMatchPopularity(ctx context.Context, scores map[string]float64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for matchID, score := range scores {
+		if _, err := tx.ExecContext(ctx, "UPDATE matches SET popularity = ? WHERE id = ?", score, matchID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
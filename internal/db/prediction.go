@@ -11,6 +11,9 @@ type Prediction struct {
 	PredictedOutcome   *string    `json:"predicted_outcome" db:"predicted_outcome"`
 	PredictedHomeScore *int       `json:"predicted_home_score" db:"predicted_home_score"`
 	PredictedAwayScore *int       `json:"predicted_away_score" db:"predicted_away_score"`
+	HomeOdds           *float64   `json:"home_odds" db:"home_odds"` // Match odds when the prediction was last saved
+	DrawOdds           *float64   `json:"draw_odds" db:"draw_odds"`
+	AwayOdds           *float64   `json:"away_odds" db:"away_odds"`
 	PointsAwarded      int        `json:"points_awarded" db:"points_awarded"`
 	CompletedAt        *time.Time `json:"completed_at" db:"completed_at"`
 	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
@@ -23,24 +26,49 @@ const (
 	MatchOutcomeDraw = "draw"
 )
 
+// SavePrediction inserts or updates the user's prediction, keeping the match odds at the time of saving
 func (s *Storage) SavePrediction(ctx context.Context, prediction Prediction) error {
 	query := `
 		INSERT INTO predictions (
-			user_id, match_id, predicted_outcome, predicted_home_score, predicted_away_score
-		) VALUES (?, ?, ?, ?, ?)
+			user_id, match_id, predicted_outcome, predicted_home_score, predicted_away_score,
+			home_odds, draw_odds, away_odds, created_at, updated_at
+		)
+		SELECT ?, ?, ?, ?, ?, m.home_odds, m.draw_odds, m.away_odds, ?, ?
+		FROM matches m
+		WHERE m.id = ?
 		ON CONFLICT(user_id, match_id) DO UPDATE SET
 			predicted_outcome = excluded.predicted_outcome,
 			predicted_home_score = excluded.predicted_home_score,
 			predicted_away_score = excluded.predicted_away_score,
-			updated_at = CURRENT_TIMESTAMP`
-	_, err := s.db.ExecContext(ctx, query,
+			home_odds = excluded.home_odds,
+			draw_odds = excluded.draw_odds,
+			away_odds = excluded.away_odds,
+			updated_at = excluded.updated_at`
+	now := s.now()
+	res, err := s.db.ExecContext(ctx, query,
 		prediction.UserID,
 		prediction.MatchID,
 		prediction.PredictedOutcome,
 		prediction.PredictedHomeScore,
 		prediction.PredictedAwayScore,
+		now,
+		now,
+		prediction.MatchID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	// The odds come from the match row, without one nothing is inserted
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
 }
 
 func (s *Storage) DeletePrediction(ctx context.Context, userID, matchID string) error {
@@ -57,6 +85,9 @@ func (s *Storage) GetUserPredictionByMatchID(ctx context.Context, uid, matchID s
 			predicted_outcome,
 			predicted_home_score,
 			predicted_away_score,
+			home_odds,
+			draw_odds,
+			away_odds,
 			points_awarded,
 			created_at,
 			updated_at,
@@ -71,6 +102,9 @@ func (s *Storage) GetUserPredictionByMatchID(ctx context.Context, uid, matchID s
 		&prediction.PredictedOutcome,
 		&prediction.PredictedHomeScore,
 		&prediction.PredictedAwayScore,
+		&prediction.HomeOdds,
+		&prediction.DrawOdds,
+		&prediction.AwayOdds,
 		&prediction.PointsAwarded,
 		&prediction.CreatedAt,
 		&prediction.UpdatedAt,
@@ -94,6 +128,9 @@ func (s *Storage) GetPredictionsByUserID(ctx context.Context, uid string, opts .
 			p.predicted_outcome,
 			p.predicted_home_score,
 			p.predicted_away_score,
+			p.home_odds,
+			p.draw_odds,
+			p.away_odds,
 			p.points_awarded,
 			p.created_at,
 			p.updated_at,
@@ -139,6 +176,9 @@ func (s *Storage) GetPredictionsByUserID(ctx context.Context, uid string, opts .
 			&p.PredictedOutcome,
 			&p.PredictedHomeScore,
 			&p.PredictedAwayScore,
+			&p.HomeOdds,
+			&p.DrawOdds,
+			&p.AwayOdds,
 			&p.PointsAwarded,
 			&p.CreatedAt,
 			&p.UpdatedAt,
@@ -185,6 +225,9 @@ func (s *Storage) GetPredictionsForMatch(ctx context.Context, matchID string) ([
 			predicted_outcome,
 			predicted_home_score,
 			predicted_away_score,
+			home_odds,
+			draw_odds,
+			away_odds,
 			points_awarded,
 			created_at,
 			updated_at,
@@ -207,6 +250,9 @@ func (s *Storage) GetPredictionsForMatch(ctx context.Context, matchID string) ([
 			&prediction.PredictedOutcome,
 			&prediction.PredictedHomeScore,
 			&prediction.PredictedAwayScore,
+			&prediction.HomeOdds,
+			&prediction.DrawOdds,
+			&prediction.AwayOdds,
 			&prediction.PointsAwarded,
 			&prediction.CreatedAt,
 			&prediction.UpdatedAt,
@@ -225,9 +271,10 @@ func (s *Storage) GetPredictionsForMatch(ctx context.Context, matchID string) ([
 func (s *Storage) UpdatePredictionResult(ctx context.Context, matchID, userID string, points int) error {
 	query := `
 		UPDATE predictions
-		SET points_awarded = ?, completed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		SET points_awarded = ?, completed_at = ?, updated_at = ?
 		WHERE match_id = ? AND user_id = ?`
-	_, err := s.db.ExecContext(ctx, query, points, matchID, userID)
+	now := s.now()
+	_, err := s.db.ExecContext(ctx, query, points, now, now, matchID, userID)
 
 	return err
 }
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// ReminderCandidate is an upcoming match the user hasn't predicted although
+// they usually predict its competition or one of its teams
+type ReminderCandidate struct {
+	User  User
+	Match Match
+}
+
+// ReminderHabits decide which matches a user "usually predicts": at least MinCompetition
+// predictions in the match's competition or MinTeam predictions on matches of either
+// team, counting matches played since Since
+type ReminderHabits struct {
+	Since          time.Time
+	MinCompetition int
+	MinTeam        int
+}
+
+// GetKickoffReminderCandidates returns unpredicted scheduled matches kicking off between
+// from and to for the users whose habits include them, ordered by user and kickoff.
+// Users who turned reminders off are left out.
+func (s *Storage) GetKickoffReminderCandidates(ctx context.Context, from, to time.Time, habits ReminderHabits) ([]ReminderCandidate, error) {
+	query := `
+		WITH history AS (
+			SELECT p.user_id, COALESCE(m.competition_code, m.tournament) AS competition, m.home_team_id, m.away_team_id
+			FROM predictions p
+			JOIN matches m ON m.id = p.match_id
+			WHERE datetime(m.match_date) >= datetime(?)
+		),
+		competition_habits AS (
+			SELECT user_id, competition
+			FROM history
+			GROUP BY user_id, competition
+			HAVING COUNT(*) >= ?
+		),
+		team_habits AS (
+			SELECT user_id, team_id
+			FROM (
+				SELECT user_id, home_team_id AS team_id FROM history
+				UNION ALL
+				SELECT user_id, away_team_id AS team_id FROM history
+			)
+			GROUP BY user_id, team_id
+			HAVING COUNT(*) >= ?
+		),
+		upcoming AS (
+			SELECT id, tournament, COALESCE(competition_code, tournament) AS competition, home_team_id, away_team_id, match_date
+			FROM matches
+			WHERE status = ? AND datetime(match_date) >= datetime(?) AND datetime(match_date) < datetime(?)
+		),
+		candidates AS (
+			SELECT c.user_id, m.id AS match_id
+			FROM upcoming m
+			JOIN competition_habits c ON c.competition = m.competition
+			UNION
+			SELECT t.user_id, m.id AS match_id
+			FROM upcoming m
+			JOIN team_habits t ON t.team_id IN (m.home_team_id, m.away_team_id)
+		)
+		SELECT
+			u.id, u.username, u.language_code, u.chat_id,
+			m.id, m.tournament, m.match_date, m.home_team_id, m.away_team_id,
+			th.name, th.short_name, ta.name, ta.short_name
+		FROM candidates c
+		JOIN users u ON u.id = c.user_id
+		JOIN upcoming m ON m.id = c.match_id
+		JOIN teams th ON th.id = m.home_team_id
+		JOIN teams ta ON ta.id = m.away_team_id
+		LEFT JOIN notification_settings ns ON ns.user_id = c.user_id
+		WHERE COALESCE(ns.reminders, 1) = 1
+		  AND NOT EXISTS (SELECT 1 FROM predictions p WHERE p.user_id = c.user_id AND p.match_id = c.match_id)
+		ORDER BY u.id, m.match_date, m.id`
+
+	rows, err := s.db.QueryContext(ctx, query,
+		habits.Since, habits.MinCompetition, habits.MinTeam,
+		MatchStatusScheduled, from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []ReminderCandidate
+	for rows.Next() {
+		var c ReminderCandidate
+		if err := rows.Scan(
+			&c.User.ID,
+			&c.User.Username,
+			&c.User.LanguageCode,
+			&c.User.ChatID,
+			&c.Match.ID,
+			&c.Match.Tournament,
+			&c.Match.MatchDate,
+			&c.Match.HomeTeamID,
+			&c.Match.AwayTeamID,
+			&c.Match.HomeTeam.Name,
+			&c.Match.HomeTeam.ShortName,
+			&c.Match.AwayTeam.Name,
+			&c.Match.AwayTeam.ShortName,
+		); err != nil {
+			return nil, err
+		}
+		c.Match.HomeTeam.ID = c.Match.HomeTeamID
+		c.Match.AwayTeam.ID = c.Match.AwayTeamID
+		candidates = append(candidates, c)
+	}
+
+	return candidates, rows.Err()
+}
@@ -113,3 +113,77 @@ func (s *Storage) GetActiveSeason(ctx context.Context, t string) (Season, error)
 
 	return season, nil
 }
+
+// GetSeasonsForDate returns every season, active or not, that t falls into.
+// end_date holds the start of a season's last day, so that whole day is included.
+func (s *Storage) GetSeasonsForDate(ctx context.Context, t time.Time) ([]Season, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			start_date,
+			end_date,
+			is_active,
+			type
+		FROM seasons
+		WHERE datetime(start_date) <= datetime(?) AND datetime(?) < datetime(end_date, '+1 day')`
+
+	rows, err := s.db.QueryContext(ctx, query, t.UTC(), t.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seasons := make([]Season, 0)
+	for rows.Next() {
+		var season Season
+		if err := rows.Scan(
+			&season.ID,
+			&season.Name,
+			&season.StartDate,
+			&season.EndDate,
+			&season.IsActive,
+			&season.Type,
+		); err != nil {
+			return nil, err
+		}
+		seasons = append(seasons, season)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return seasons, nil
+}
+
+func (s *Storage) GetSeasonByID(ctx context.Context, id string) (Season, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			start_date,
+			end_date,
+			is_active,
+			type
+		FROM seasons
+		WHERE id = ?`
+
+	var season Season
+	err := s.db.QueryRowContext(ctx, query, id).Scan(
+		&season.ID,
+		&season.Name,
+		&season.StartDate,
+		&season.EndDate,
+		&season.IsActive,
+		&season.Type,
+	)
+
+	if err != nil && IsNoRowsError(err) {
+		return Season{}, ErrNotFound
+	} else if err != nil {
+		return Season{}, err
+	}
+
+	return season, nil
+}
@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type Standing struct {
+	CompetitionCode string    `db:"competition_code" json:"competition_code"`
+	Group           string    `db:"group_name" json:"group"`
+	TeamID          string    `db:"team_id" json:"team_id"`
+	Position        int       `db:"position" json:"position"`
+	Played          int       `db:"played" json:"played"`
+	Won             int       `db:"won" json:"won"`
+	Draw            int       `db:"draw" json:"draw"`
+	Lost            int       `db:"lost" json:"lost"`
+	GoalsFor        int       `db:"goals_for" json:"goals_for"`
+	GoalsAgainst    int       `db:"goals_against" json:"goals_against"`
+	GoalDifference  int       `db:"goal_difference" json:"goal_difference"`
+	Points          int       `db:"points" json:"points"`
+	Form            *string   `db:"form" json:"form"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	Team            Team      `db:"-" json:"team"`
+}
+
+// ReplaceStandings swaps the competition's tables for the given rows
+func (s *Storage) ReplaceStandings(ctx context.Context, competitionCode string, standings []Standing) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM standings WHERE competition_code = ?", competitionCode); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO standings (competition_code, group_name, team_id, position, played, won, draw, lost,
+		                       goals_for, goals_against, goal_difference, points, form, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	now := s.now()
+
+	for _, st := range standings {
+		if _, err := tx.ExecContext(ctx, query,
+			competitionCode,
+			st.Group,
+			st.TeamID,
+			st.Position,
+			st.Played,
+			st.Won,
+			st.Draw,
+			st.Lost,
+			st.GoalsFor,
+			st.GoalsAgainst,
+			st.GoalDifference,
+			st.Points,
+			st.Form,
+			now,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetStandings returns the competition's tables ordered by group and position
+func (s *Storage) GetStandings(ctx context.Context, competitionCode string) ([]Standing, error) {
+	query := `
+		SELECT
+			s.competition_code,
+			s.group_name,
+			s.team_id,
+			s.position,
+			s.played,
+			s.won,
+			s.draw,
+			s.lost,
+			s.goals_for,
+			s.goals_against,
+			s.goal_difference,
+			s.points,
+			s.form,
+			s.updated_at,
+			t.id,
+			t.name,
+			COALESCE(t.short_name, ''),
+			COALESCE(t.crest_url, ''),
+			COALESCE(t.country, ''),
+			COALESCE(t.abbreviation, '')
+		FROM standings s
+		JOIN teams t ON t.id = s.team_id
+		WHERE s.competition_code = ?
+		ORDER BY s.group_name, s.position`
+
+	rows, err := s.db.QueryContext(ctx, query, competitionCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	standings := make([]Standing, 0)
+	for rows.Next() {
+		var st Standing
+		if err := rows.Scan(
+			&st.CompetitionCode,
+			&st.Group,
+			&st.TeamID,
+			&st.Position,
+			&st.Played,
+			&st.Won,
+			&st.Draw,
+			&st.Lost,
+			&st.GoalsFor,
+			&st.GoalsAgainst,
+			&st.GoalDifference,
+			&st.Points,
+			&st.Form,
+			&st.UpdatedAt,
+			&st.Team.ID,
+			&st.Team.Name,
+			&st.Team.ShortName,
+			&st.Team.CrestURL,
+			&st.Team.Country,
+			&st.Team.Abbreviation,
+		); err != nil {
+			return nil, err
+		}
+		standings = append(standings, st)
+	}
+
+	return standings, rows.Err()
+}
+
+// GetLeaguePositions maps team IDs to their position in the competition's table
+func (s *Storage) GetLeaguePositions(ctx context.Context, competitionCode string) (map[string]int, error) {
+	query := `
+		SELECT team_id, MIN(position)
+		FROM standings
+		WHERE competition_code = ?
+		GROUP BY team_id`
+
+	rows, err := s.db.QueryContext(ctx, query, competitionCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	positions := make(map[string]int)
+	for rows.Next() {
+		var teamID string
+		var position int
+		if err := rows.Scan(&teamID, &position); err != nil {
+			return nil, err
+		}
+		positions[teamID] = position
+	}
+
+	return positions, rows.Err()
+}
@@ -38,9 +38,9 @@ func (s *Storage) GetActiveSubscription(ctx context.Context, uid string) (Subscr
 	query := `
 		SELECT id, user_id, start_date, end_date, is_paid, created_at, payment_id
 		FROM subscriptions
-		WHERE user_id = ? AND end_date > CURRENT_TIMESTAMP`
+		WHERE user_id = ? AND datetime(end_date) > datetime(?)`
 	var sub Subscription
-	err := s.db.QueryRowContext(ctx, query, uid).Scan(
+	err := s.db.QueryRowContext(ctx, query, uid, s.now()).Scan(
 		&sub.ID,
 		&sub.UserID,
 		&sub.StartDate,
@@ -62,19 +62,20 @@ func (s *Storage) GetActiveSubscription(ctx context.Context, uid string) (Subscr
 func (s *Storage) SuspendSubscription(ctx context.Context, uid string) error {
 	query := `
 		UPDATE subscriptions 
-		SET end_date = CURRENT_TIMESTAMP
-		WHERE user_id = ? AND end_date > CURRENT_TIMESTAMP`
-	_, err := s.db.ExecContext(ctx, query, uid)
+		SET end_date = ?
+		WHERE user_id = ? AND datetime(end_date) > datetime(?)`
+	now := s.now()
+	_, err := s.db.ExecContext(ctx, query, now, uid, now)
 	if err != nil {
 		return err
 	}
 
 	query = `
 		UPDATE users
-		SET subscription_active = false, subscription_expiry = CURRENT_TIMESTAMP
+		SET subscription_active = false, subscription_expiry = ?
 		WHERE id = ?`
 
-	_, err = s.db.ExecContext(ctx, query, uid)
+	_, err = s.db.ExecContext(ctx, query, now, uid)
 	if err != nil {
 		return err
 	}
@@ -15,8 +15,8 @@ type Survey struct {
 
 func (s *Storage) SaveSurvey(ctx context.Context, survey Survey) error {
 	query := `
-        INSERT INTO surveys (id, user_id, feature, preference)
-        VALUES (?, ?, ?, ?)
+        INSERT INTO surveys (id, user_id, feature, preference, created_at)
+        VALUES (?, ?, ?, ?, ?)
         ON CONFLICT DO NOTHING` // Prevents duplicate submissions if needed
 
 	_, err := s.db.ExecContext(ctx, query,
@@ -24,6 +24,7 @@ func (s *Storage) SaveSurvey(ctx context.Context, survey Survey) error {
 		survey.UserID,
 		survey.Feature,
 		survey.Preference,
+		s.now(),
 	)
 	return err
 }
@@ -1,7 +1,11 @@
 package db
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
+// GetTeamByName finds a team by its name or one of its aliases
 func (s *Storage) GetTeamByName(ctx context.Context, name string) (Team, error) {
 	query := `
 		SELECT
@@ -12,10 +16,12 @@ func (s *Storage) GetTeamByName(ctx context.Context, name string) (Team, error)
 			country,
 			abbreviation
 		FROM teams
-		WHERE name = ?`
+		WHERE name = ? OR id IN (SELECT team_id FROM team_aliases WHERE alias = ?)
+		ORDER BY name = ? DESC
+		LIMIT 1`
 
 	var team Team
-	err := s.db.QueryRowContext(ctx, query, name).Scan(
+	err := s.db.QueryRowContext(ctx, query, name, name, name).Scan(
 		&team.ID,
 		&team.Name,
 		&team.ShortName,
@@ -127,3 +133,110 @@ func (s *Storage) ListTeams(ctx context.Context) ([]Team, error) {
 
 	return teams, nil
 }
+
+// HasTeamAlias reports whether the alias already points to the team
+func (s *Storage) HasTeamAlias(ctx context.Context, teamID, alias string) (bool, error) {
+	var exists bool
+	err := s.db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM team_aliases WHERE alias = ? AND team_id = ?)", alias, teamID,
+	).Scan(&exists)
+	return exists, err
+}
+
+// AddTeamAlias maps an alternative name to the team and clears it from the unresolved report
+func (s *Storage) AddTeamAlias(ctx context.Context, teamID, alias string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO team_aliases (alias, team_id, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (alias) DO UPDATE SET team_id = excluded.team_id`
+
+	if _, err := tx.ExecContext(ctx, query, alias, teamID, s.now()); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM unresolved_teams WHERE name = ?", alias); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type UnresolvedTeam struct {
+	Name           string    `db:"name" json:"name"`
+	Competition    string    `db:"competition" json:"competition"`
+	ProviderTeamID *string   `db:"provider_team_id" json:"provider_team_id"`
+	LastMatchID    *string   `db:"last_match_id" json:"last_match_id"`
+	LastError      *string   `db:"last_error" json:"last_error"`
+	Occurrences    int       `db:"occurrences" json:"occurrences"`
+	FirstSeenAt    time.Time `db:"first_seen_at" json:"first_seen_at"`
+	LastSeenAt     time.Time `db:"last_seen_at" json:"last_seen_at"`
+}
+
+func (s *Storage) RecordUnresolvedTeam(ctx context.Context, team UnresolvedTeam) error {
+	query := `
+		INSERT INTO unresolved_teams (name, competition, provider_team_id, last_match_id, last_error, first_seen_at, last_seen_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (name, competition) DO UPDATE SET
+			provider_team_id = excluded.provider_team_id,
+			last_match_id = excluded.last_match_id,
+			last_error = excluded.last_error,
+			occurrences = occurrences + 1,
+			last_seen_at = excluded.last_seen_at`
+
+	now := s.now()
+	_, err := s.db.ExecContext(ctx, query, team.Name, team.Competition, team.ProviderTeamID, team.LastMatchID, team.LastError, now, now)
+	return err
+}
+
+// ResolveTeam removes a team name from the unresolved report once a match with it got stored
+func (s *Storage) ResolveTeam(ctx context.Context, name string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM unresolved_teams WHERE name = ?", name)
+	return err
+}
+
+func (s *Storage) ListUnresolvedTeams(ctx context.Context) ([]UnresolvedTeam, error) {
+	query := `
+		SELECT
+			name,
+			competition,
+			provider_team_id,
+			last_match_id,
+			last_error,
+			occurrences,
+			first_seen_at,
+			last_seen_at
+		FROM unresolved_teams
+		ORDER BY last_seen_at DESC`
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := make([]UnresolvedTeam, 0)
+	for rows.Next() {
+		var team UnresolvedTeam
+		if err := rows.Scan(
+			&team.Name,
+			&team.Competition,
+			&team.ProviderTeamID,
+			&team.LastMatchID,
+			&team.LastError,
+			&team.Occurrences,
+			&team.FirstSeenAt,
+			&team.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+
+	return teams, rows.Err()
+}
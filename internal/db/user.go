@@ -31,14 +31,22 @@ type User struct {
 	Badges             []Badge    `db:"badges"`
 	SubscriptionActive bool       `db:"subscription_active"`
 	SubscriptionExpiry *time.Time `db:"subscription_expiry"`
+	Privacy            string     `db:"privacy"`
 }
 
+const (
+	PrivacyPublic    = "public"
+	PrivacyFollowers = "followers"
+	PrivacyPrivate   = "private"
+)
+
 type Badge struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Color     string    `json:"color"`
-	Icon      string    `json:"icon"`
-	AwardedAt time.Time `json:"awarded_at"`
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Color       string    `json:"color"`
+	Icon        string    `json:"icon"`
+	Description string    `json:"description,omitempty"`
+	AwardedAt   time.Time `json:"awarded_at"`
 }
 
 func UnmarshalJSONToSlice[T any](src interface{}) ([]T, error) {
@@ -85,10 +93,10 @@ func IsForeignKeyViolationError(err error) bool {
 
 func (s *Storage) CreateUser(user User) error {
 	query := `
-		INSERT INTO users (id, first_name, last_name, username, language_code, chat_id, avatar_url, referred_by)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+		INSERT INTO users (id, first_name, last_name, username, language_code, chat_id, avatar_url, referred_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	_, err := s.db.Exec(query, user.ID, user.FirstName, user.LastName, user.Username, user.LanguageCode, user.ChatID, user.AvatarURL, user.ReferredBy)
+	_, err := s.db.Exec(query, user.ID, user.FirstName, user.LastName, user.Username, user.LanguageCode, user.ChatID, user.AvatarURL, user.ReferredBy, s.now())
 	return err
 }
 
@@ -109,6 +117,7 @@ func (s *Storage) getUserBy(condition string, value interface{}) (User, error) {
 				u.longest_win_streak,
 				u.subscription_active,
 				u.subscription_expiry,
+				COALESCE(u.privacy, 'public'),
 				CASE 
 					WHEN u.favorite_team_id IS NOT NULL THEN 
 						json_object(
@@ -156,6 +165,7 @@ func (s *Storage) getUserBy(condition string, value interface{}) (User, error) {
 		&user.LongestWinStreak,
 		&user.SubscriptionActive,
 		&user.SubscriptionExpiry,
+		&user.Privacy,
 		&favoriteTeamJSON,
 		&badgeJSON,
 	); err != nil && IsNoRowsError(err) {
@@ -269,10 +279,11 @@ func (s *Storage) UpdateUserInformation(ctx context.Context, user User) error {
 		    username = ?,
 		    avatar_url = ?,
 		    language_code = ?,
-		    favorite_team_id = ?
+		    favorite_team_id = ?,
+		    privacy = ?
 		WHERE id = ?`
 
-	_, err := s.db.ExecContext(ctx, query, user.FirstName, user.LastName, user.Username, user.AvatarURL, user.LanguageCode, user.FavoriteTeamID, user.ID)
+	_, err := s.db.ExecContext(ctx, query, user.FirstName, user.LastName, user.Username, user.AvatarURL, user.LanguageCode, user.FavoriteTeamID, user.Privacy, user.ID)
 	return err
 }
 
@@ -354,10 +365,10 @@ func (s *Storage) GetAllUsersWithFavoriteTeam(ctx context.Context) ([]User, erro
 
 func (s *Storage) FollowUser(ctx context.Context, followerID, followingID string) error {
 	query := `
-		INSERT INTO user_followers (follower_id, following_id)
-		VALUES (?, ?)
+		INSERT INTO user_followers (follower_id, following_id, created_at)
+		VALUES (?, ?, ?)
 	`
-	_, err := s.db.ExecContext(ctx, query, followerID, followingID)
+	_, err := s.db.ExecContext(ctx, query, followerID, followingID, s.now())
 	if err != nil {
 		if IsUniqueViolationError(err) {
 			return fmt.Errorf("already following user: %w", err)
@@ -460,7 +471,7 @@ func (s *Storage) RecordUserLogin(ctx context.Context, userID string) error {
         VALUES (?, ?, ?)
     `
 	id := nanoid.Must() // Assuming you have a nanoid package for unique IDs
-	_, err := s.db.ExecContext(ctx, query, id, userID, time.Now())
+	_, err := s.db.ExecContext(ctx, query, id, userID, s.now())
 	return err
 }
 
@@ -469,10 +480,10 @@ func (s *Storage) HasLoggedInToday(ctx context.Context, userID string) (bool, er
         SELECT COUNT(*)
         FROM user_logins
         WHERE user_id = ?
-        AND DATE(login_time) = DATE('now')
+        AND DATE(login_time) = DATE(?)
     `
 	var count int
-	err := s.db.QueryRowContext(ctx, query, userID).Scan(&count)
+	err := s.db.QueryRowContext(ctx, query, userID, s.now()).Scan(&count)
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,228 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+)
+
+// trigramLength is the token size of the users_fts trigram tokenizer;
+// shorter terms can't be looked up in the index.
+const trigramLength = 3
+
+const (
+	SuggestionReasonSameTeam     = "same_team"
+	SuggestionReasonTopPredictor = "top_predictor"
+)
+
+type SuggestedUser struct {
+	User
+	Reason string `db:"reason"`
+}
+
+const searchUserColumns = `
+	u.id,
+	u.first_name,
+	u.last_name,
+	u.username,
+	u.avatar_url,
+	u.total_predictions,
+	u.correct_predictions,
+	COALESCE(u.privacy, 'public')`
+
+// SearchUsers looks people up by username and first/last name.
+// Substring matches come first, then fuzzy matches sharing trigrams with the query to tolerate typos.
+func (s *Storage) SearchUsers(ctx context.Context, query string, limit int) ([]User, error) {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return []User{}, nil
+	}
+
+	var users []User
+	var err error
+
+	short := false
+	for _, term := range terms {
+		if utf8.RuneCountInString(term) < trigramLength {
+			short = true
+		}
+	}
+
+	if short {
+		users, err = s.searchUsersByPrefix(ctx, terms, limit)
+	} else {
+		users, err = s.searchUsersByMatch(ctx, strings.Join(quoteTerms(terms), " "), nil, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) >= limit {
+		return users, nil
+	}
+
+	var trigrams []string
+	for _, term := range terms {
+		trigrams = append(trigrams, termTrigrams(term)...)
+	}
+	if len(trigrams) == 0 {
+		return users, nil
+	}
+
+	exclude := make([]string, 0, len(users))
+	for _, user := range users {
+		exclude = append(exclude, user.ID)
+	}
+
+	fuzzy, err := s.searchUsersByMatch(ctx, strings.Join(quoteTerms(trigrams), " OR "), exclude, limit-len(users))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(users, fuzzy...), nil
+}
+
+func (s *Storage) searchUsersByMatch(ctx context.Context, match string, exclude []string, limit int) ([]User, error) {
+	query := `
+		SELECT` + searchUserColumns + `
+		FROM users_fts f
+		JOIN users u ON u.id = f.user_id
+		WHERE users_fts MATCH ?
+		AND u.id NOT IN (SELECT value FROM json_each(?))
+		ORDER BY bm25(users_fts, 2.0, 1.0, 1.0), u.correct_predictions DESC
+		LIMIT ?`
+
+	if exclude == nil {
+		exclude = []string{}
+	}
+	excludeJSON, err := json.Marshal(exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.queryUsers(ctx, query, match, string(excludeJSON), limit)
+}
+
+// searchUsersByPrefix handles queries with terms too short for the trigram index
+func (s *Storage) searchUsersByPrefix(ctx context.Context, terms []string, limit int) ([]User, error) {
+	var conditions []string
+	var args []interface{}
+	for _, term := range terms {
+		conditions = append(conditions, `(
+			lower(u.username) LIKE ? ESCAPE '\' OR
+			lower(u.first_name) LIKE ? ESCAPE '\' OR
+			lower(u.last_name) LIKE ? ESCAPE '\')`)
+		pattern := escapeLike(term) + "%"
+		args = append(args, pattern, pattern, pattern)
+	}
+
+	query := `
+		SELECT` + searchUserColumns + `
+		FROM users u
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY length(u.username), u.correct_predictions DESC
+		LIMIT ?`
+
+	args = append(args, limit)
+
+	return s.queryUsers(ctx, query, args...)
+}
+
+// SuggestUsersToFollow returns fans of the user's favorite team first, then the best predictors.
+// Private profiles and people already followed are left out.
+func (s *Storage) SuggestUsersToFollow(ctx context.Context, userID string, limit int) ([]SuggestedUser, error) {
+	query := `
+		SELECT` + searchUserColumns + `,
+			CASE WHEN me.favorite_team_id IS NOT NULL AND u.favorite_team_id = me.favorite_team_id
+				THEN ? ELSE ? END AS reason
+		FROM users u
+		JOIN users me ON me.id = ?
+		WHERE u.id != me.id
+		AND COALESCE(u.privacy, 'public') != 'private'
+		AND NOT EXISTS (
+			SELECT 1 FROM user_followers uf
+			WHERE uf.follower_id = me.id AND uf.following_id = u.id
+		)
+		AND (u.favorite_team_id = me.favorite_team_id OR u.correct_predictions > 0)
+		ORDER BY reason = ? DESC, u.correct_predictions DESC, u.total_predictions DESC
+		LIMIT ?`
+
+	rows, err := s.db.QueryContext(ctx, query,
+		SuggestionReasonSameTeam, SuggestionReasonTopPredictor, userID, SuggestionReasonSameTeam, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := make([]SuggestedUser, 0)
+	for rows.Next() {
+		var suggestion SuggestedUser
+		if err := rows.Scan(
+			&suggestion.ID,
+			&suggestion.FirstName,
+			&suggestion.LastName,
+			&suggestion.Username,
+			&suggestion.AvatarURL,
+			&suggestion.TotalPredictions,
+			&suggestion.CorrectPredictions,
+			&suggestion.Privacy,
+			&suggestion.Reason,
+		); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, rows.Err()
+}
+
+func (s *Storage) queryUsers(ctx context.Context, query string, args ...interface{}) ([]User, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]User, 0)
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(
+			&user.ID,
+			&user.FirstName,
+			&user.LastName,
+			&user.Username,
+			&user.AvatarURL,
+			&user.TotalPredictions,
+			&user.CorrectPredictions,
+			&user.Privacy,
+		); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// quoteTerms turns user input into FTS5 string literals so it can't inject query syntax
+func quoteTerms(terms []string) []string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return quoted
+}
+
+func termTrigrams(term string) []string {
+	runes := []rune(term)
+	var trigrams []string
+	for i := 0; i+trigramLength <= len(runes); i++ {
+		trigrams = append(trigrams, string(runes[i:i+trigramLength]))
+	}
+	return trigrams
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
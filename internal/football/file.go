@@ -0,0 +1,260 @@
+package football
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// File is a provider reading fixtures and results from a local directory, one subdirectory
+// per competition code holding teams.json or teams.csv and matches.json or matches.csv.
+// JSON files hold arrays of Team and Match. CSV files have a header row with the columns
+//
+//	teams.csv:     id,name,short_name,tla,crest,area_code
+//	matches.csv:   id,utc_date,status,matchday,home_team_id,home_team,away_team_id,away_team,home_score,away_score,home_odds,draw_odds,away_odds
+//	standings.csv: position,team_id,team,played,won,draw,lost,goals_for,goals_against,points,form
+//
+// matches.csv may also have season, stage and group columns, standings.csv a group column.
+// Empty scores and odds are read as missing.
+type File struct {
+	dir string
+}
+
+func NewFile(dir string) *File {
+	return &File{dir: dir}
+}
+
+func (f *File) GetTeams(_ context.Context, competition string) ([]Team, error) {
+	var teams []Team
+	found, err := f.readJSON(competition, "teams.json", &teams)
+	if err != nil || found {
+		return teams, err
+	}
+
+	records, err := f.readCSV(competition, "teams.csv")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range records {
+		teams = append(teams, Team{
+			ID:        r["id"],
+			Name:      r["name"],
+			ShortName: r["short_name"],
+			TLA:       r["tla"],
+			Crest:     r["crest"],
+			AreaCode:  r["area_code"],
+		})
+	}
+
+	return teams, nil
+}
+
+func (f *File) GetMatches(_ context.Context, competition string, filter MatchFilter) ([]Match, error) {
+	var all []Match
+	found, err := f.readJSON(competition, "matches.json", &all)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		records, err := f.readCSV(competition, "matches.csv")
+		if err != nil {
+			return nil, err
+		}
+
+		for i, r := range records {
+			match, err := parseMatchRecord(competition, r)
+			if err != nil {
+				return nil, fmt.Errorf("matches.csv row %d: %w", i+2, err)
+			}
+			all = append(all, match)
+		}
+	}
+
+	matches := make([]Match, 0, len(all))
+	for _, match := range all {
+		if filter.Contains(match.UTCDate) {
+			matches = append(matches, match)
+		}
+	}
+
+	return matches, nil
+}
+
+func parseMatchRecord(competition string, r map[string]string) (Match, error) {
+	date, err := time.Parse(time.RFC3339, r["utc_date"])
+	if err != nil {
+		return Match{}, fmt.Errorf("invalid utc_date: %w", err)
+	}
+
+	match := Match{
+		ID:          r["id"],
+		Competition: Competition{Code: competition, Name: competition},
+		Season:      r["season"],
+		UTCDate:     date,
+		Status:      r["status"],
+		Stage:       r["stage"],
+		Group:       r["group"],
+		HomeTeam:    MatchTeam{ID: r["home_team_id"], Name: r["home_team"]},
+		AwayTeam:    MatchTeam{ID: r["away_team_id"], Name: r["away_team"]},
+	}
+
+	if match.Status == "" {
+		match.Status = StatusScheduled
+	}
+
+	if val := r["matchday"]; val != "" {
+		if match.Matchday, err = strconv.Atoi(val); err != nil {
+			return Match{}, fmt.Errorf("invalid matchday: %w", err)
+		}
+	}
+
+	if match.Score.Home, err = optionalInt(r["home_score"]); err != nil {
+		return Match{}, fmt.Errorf("invalid home_score: %w", err)
+	}
+	if match.Score.Away, err = optionalInt(r["away_score"]); err != nil {
+		return Match{}, fmt.Errorf("invalid away_score: %w", err)
+	}
+	if match.Odds.HomeWin, err = optionalFloat(r["home_odds"]); err != nil {
+		return Match{}, fmt.Errorf("invalid home_odds: %w", err)
+	}
+	if match.Odds.Draw, err = optionalFloat(r["draw_odds"]); err != nil {
+		return Match{}, fmt.Errorf("invalid draw_odds: %w", err)
+	}
+	if match.Odds.AwayWin, err = optionalFloat(r["away_odds"]); err != nil {
+		return Match{}, fmt.Errorf("invalid away_odds: %w", err)
+	}
+
+	return match, nil
+}
+
+func (f *File) GetStandings(_ context.Context, competition string) ([]Standing, error) {
+	var standings []Standing
+	found, err := f.readJSON(competition, "standings.json", &standings)
+	if err != nil || found {
+		return standings, err
+	}
+
+	records, err := f.readCSV(competition, "standings.csv")
+	if err != nil {
+		return nil, err
+	}
+
+	for i, r := range records {
+		standing := Standing{
+			Group: r["group"],
+			Team:  MatchTeam{ID: r["team_id"], Name: r["team"]},
+			Form:  r["form"],
+		}
+
+		fields := map[string]*int{
+			"position":      &standing.Position,
+			"played":        &standing.PlayedGames,
+			"won":           &standing.Won,
+			"draw":          &standing.Draw,
+			"lost":          &standing.Lost,
+			"goals_for":     &standing.GoalsFor,
+			"goals_against": &standing.GoalsAgainst,
+			"points":        &standing.Points,
+		}
+		for column, dst := range fields {
+			n, err := optionalInt(r[column])
+			if err != nil {
+				return nil, fmt.Errorf("standings.csv row %d: invalid %s: %w", i+2, column, err)
+			}
+			if n != nil {
+				*dst = *n
+			}
+		}
+		standing.GoalDifference = standing.GoalsFor - standing.GoalsAgainst
+
+		standings = append(standings, standing)
+	}
+
+	return standings, nil
+}
+
+// readJSON decodes the file into dst, reporting false if it doesn't exist
+func (f *File) readJSON(competition, name string, dst interface{}) (bool, error) {
+	file, err := os.Open(filepath.Join(f.dir, competition, name))
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(dst); err != nil {
+		return true, fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+
+	return true, nil
+}
+
+// readCSV returns rows keyed by header column; a missing file means no data
+func (f *File) readCSV(competition, name string) ([]map[string]string, error) {
+	file, err := os.Open(filepath.Join(f.dir, competition, name))
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	header, err := reader.Read()
+	if err != nil && errors.Is(err, io.EOF) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	var records []map[string]string
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		record := make(map[string]string, len(header))
+		for i, column := range header {
+			record[strings.TrimSpace(column)] = strings.TrimSpace(row[i])
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func optionalInt(val string) (*int, error) {
+	if val == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func optionalFloat(val string) (*float64, error) {
+	if val == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
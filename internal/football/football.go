@@ -0,0 +1,115 @@
+// Package football defines vendor-neutral football data and the providers that supply it.
+package football
+
+import "time"
+
+// Match statuses shared by all providers
+const (
+	StatusScheduled = "scheduled"
+	StatusLive      = "live"
+	StatusFinished  = "finished"
+	StatusPostponed = "postponed"
+	StatusCancelled = "cancelled"
+	StatusUnknown   = "unknown"
+)
+
+type Team struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ShortName string `json:"short_name"`
+	TLA       string `json:"tla"`
+	Crest     string `json:"crest"`
+	AreaCode  string `json:"area_code"`
+}
+
+type Competition struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Emblem string `json:"emblem"`
+}
+
+type Area struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type Referee struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // REFEREE, ASSISTANT_REFEREE_N1, VIDEO_ASSISTANT_REFEREE_N1, ...
+	Nationality string `json:"nationality"`
+}
+
+type MatchTeam struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	ShortName  string `json:"short_name"`
+	TLA        string `json:"tla"`
+	Crest      string `json:"crest"`
+	LeagueRank *int   `json:"league_rank"`
+}
+
+type Score struct {
+	Home *int `json:"home"`
+	Away *int `json:"away"`
+}
+
+// Odds are decimal odds for each outcome, nil when the provider has none
+type Odds struct {
+	HomeWin *float64 `json:"home_win"`
+	Draw    *float64 `json:"draw"`
+	AwayWin *float64 `json:"away_win"`
+}
+
+type Match struct {
+	ID          string      `json:"id"`
+	Competition Competition `json:"competition"`
+	Season      string      `json:"season"` // Provider's ID of the competition season, matchdays restart every season
+	Area        Area        `json:"area"`
+	UTCDate     time.Time   `json:"utc_date"`
+	Status      string      `json:"status"`
+	Matchday    int         `json:"matchday"` // Zero when the competition has no matchdays
+	Stage       string      `json:"stage"`
+	Group       string      `json:"group"`
+	Referees    []Referee   `json:"referees"`
+	LastUpdated time.Time   `json:"last_updated"`
+	HomeTeam    MatchTeam   `json:"home_team"`
+	AwayTeam    MatchTeam   `json:"away_team"`
+	Score       Score       `json:"score"` // Full time score
+	Odds        Odds        `json:"odds"`
+}
+
+// Standing is a team's row in a league table
+type Standing struct {
+	Group          string    `json:"group"` // Empty for single-table leagues
+	Position       int       `json:"position"`
+	Team           MatchTeam `json:"team"`
+	PlayedGames    int       `json:"played_games"`
+	Won            int       `json:"won"`
+	Draw           int       `json:"draw"`
+	Lost           int       `json:"lost"`
+	GoalsFor       int       `json:"goals_for"`
+	GoalsAgainst   int       `json:"goals_against"`
+	GoalDifference int       `json:"goal_difference"`
+	Points         int       `json:"points"`
+	Form           string    `json:"form"` // Latest results first, e.g. "W,D,L,W,W"
+}
+
+// MatchFilter narrows down matches by kickoff date; zero values leave that side open
+type MatchFilter struct {
+	DateFrom time.Time
+	DateTo   time.Time
+}
+
+// Contains reports whether the kickoff date falls in the filter's window, days inclusive
+func (f MatchFilter) Contains(date time.Time) bool {
+	day := date.UTC().Format(dateFormat)
+	if !f.DateFrom.IsZero() && day < f.DateFrom.UTC().Format(dateFormat) {
+		return false
+	}
+	if !f.DateTo.IsZero() && day > f.DateTo.UTC().Format(dateFormat) {
+		return false
+	}
+	return true
+}
+
+const dateFormat = "2006-01-02"
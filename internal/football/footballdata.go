@@ -0,0 +1,433 @@
+package football
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// FootballData is a provider backed by the football-data.org v4 API
+type FootballData struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+	limiter *Limiter
+}
+
+const (
+	footballDataRequestsPerMinute = 10 // Free plan quota
+	maxRetries                    = 3
+	retryBaseDelay                = time.Second
+	defaultRateLimitReset         = 10 * time.Second
+)
+
+// Option customises a FootballData provider
+type Option func(*FootballData)
+
+// WithTransport sends requests through rt, e.g. a Recorder or Replayer
+func WithTransport(rt http.RoundTripper) Option {
+	return func(f *FootballData) {
+		f.client.Transport = rt
+	}
+}
+
+// WithLimiter replaces the default rate limiter, nil disables rate limiting
+func WithLimiter(l *Limiter) Option {
+	return func(f *FootballData) {
+		f.limiter = l
+	}
+}
+
+// NewFootballData creates the provider. Its limiter is shared by every job
+// using this instance, so the syncer should keep a single one.
+func NewFootballData(baseURL, apiKey string, opts ...Option) *FootballData {
+	f := &FootballData{
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		limiter: NewLimiter(footballDataRequestsPerMinute),
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
+}
+
+type apiTeam struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	ShortName string `json:"shortName"`
+	Tla       string `json:"tla"`
+	Crest     string `json:"crest"`
+	Area      struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+		Code string `json:"code"`
+	} `json:"area"`
+}
+
+type apiMatchTeam struct {
+	Id         *int    `json:"id"`
+	Name       *string `json:"name"`
+	ShortName  *string `json:"shortName"`
+	Tla        *string `json:"tla"`
+	Crest      *string `json:"crest"`
+	LeagueRank *int    `json:"leagueRank"`
+}
+
+type apiMatch struct {
+	Area struct {
+		Id   int    `json:"id"`
+		Name string `json:"name"`
+		Code string `json:"code"`
+		Flag string `json:"flag"`
+	} `json:"area"`
+	Competition struct {
+		Id     int    `json:"id"`
+		Name   string `json:"name"`
+		Code   string `json:"code"`
+		Type   string `json:"type"`
+		Emblem string `json:"emblem"`
+	} `json:"competition"`
+	Season struct {
+		Id              int     `json:"id"`
+		StartDate       string  `json:"startDate"`
+		EndDate         string  `json:"endDate"`
+		CurrentMatchday int     `json:"currentMatchday"`
+		Winner          *string `json:"winner"`
+	} `json:"season"`
+	Id          int          `json:"id"`
+	UtcDate     time.Time    `json:"utcDate"`
+	Status      string       `json:"status"`
+	Matchday    int          `json:"matchday"`
+	Stage       string       `json:"stage"`
+	Group       *string      `json:"group"`
+	LastUpdated time.Time    `json:"lastUpdated"`
+	HomeTeam    apiMatchTeam `json:"homeTeam"`
+	AwayTeam    apiMatchTeam `json:"awayTeam"`
+	Score       struct {
+		Winner   *string `json:"winner"`
+		Duration string  `json:"duration"`
+		FullTime struct {
+			Home *int `json:"home"`
+			Away *int `json:"away"`
+		} `json:"fullTime"`
+		HalfTime struct {
+			Home *int `json:"home"`
+			Away *int `json:"away"`
+		} `json:"halfTime"`
+	} `json:"score"`
+	Odds *struct {
+		HomeWin *float64 `json:"homeWin"`
+		Draw    *float64 `json:"draw"`
+		AwayWin *float64 `json:"awayWin"`
+	} `json:"odds"`
+	Referees []struct {
+		Id          int    `json:"id"`
+		Name        string `json:"name"`
+		Type        string `json:"type"`
+		Nationality string `json:"nationality"`
+	} `json:"referees"`
+}
+
+func (f *FootballData) GetTeams(ctx context.Context, competition string) ([]Team, error) {
+	var resp struct {
+		Teams []apiTeam `json:"teams"`
+	}
+
+	if err := f.fetch(ctx, fmt.Sprintf("/competitions/%s/teams", competition), &resp); err != nil {
+		return nil, err
+	}
+
+	teams := make([]Team, 0, len(resp.Teams))
+	for _, team := range resp.Teams {
+		teams = append(teams, Team{
+			ID:        strconv.Itoa(team.ID),
+			Name:      team.Name,
+			ShortName: team.ShortName,
+			TLA:       team.Tla,
+			Crest:     team.Crest,
+			AreaCode:  team.Area.Code,
+		})
+	}
+
+	return teams, nil
+}
+
+func (f *FootballData) GetMatches(ctx context.Context, competition string, filter MatchFilter) ([]Match, error) {
+	var resp struct {
+		Matches []apiMatch `json:"matches"`
+	}
+
+	// The API wants both ends of the window or none
+	params := url.Values{}
+	if !filter.DateFrom.IsZero() || !filter.DateTo.IsZero() {
+		from, to := filter.DateFrom, filter.DateTo
+		if from.IsZero() {
+			from = to.AddDate(-1, 0, 0)
+		}
+		if to.IsZero() {
+			to = from.AddDate(1, 0, 0)
+		}
+		params.Set("dateFrom", from.UTC().Format(dateFormat))
+		params.Set("dateTo", to.UTC().Format(dateFormat))
+	}
+
+	endpoint := fmt.Sprintf("/competitions/%s/matches", competition)
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	if err := f.fetch(ctx, endpoint, &resp); err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0, len(resp.Matches))
+	for _, match := range resp.Matches {
+		// Knockout fixtures are published before the teams are known
+		if match.HomeTeam.Id == nil || match.AwayTeam.Id == nil {
+			continue
+		}
+
+		// The API filters by itself, replayed season fixtures don't
+		if !filter.Contains(match.UtcDate) {
+			continue
+		}
+
+		m := Match{
+			ID: strconv.Itoa(match.Id),
+			Competition: Competition{
+				Code:   match.Competition.Code,
+				Name:   match.Competition.Name,
+				Emblem: match.Competition.Emblem,
+			},
+			Area: Area{
+				Code: match.Area.Code,
+				Name: match.Area.Name,
+			},
+			UTCDate:     match.UtcDate,
+			Status:      footballDataStatus(match.Status),
+			Matchday:    match.Matchday,
+			Stage:       match.Stage,
+			LastUpdated: match.LastUpdated,
+			HomeTeam:    toMatchTeam(match.HomeTeam),
+			AwayTeam:    toMatchTeam(match.AwayTeam),
+			Score: Score{
+				Home: match.Score.FullTime.Home,
+				Away: match.Score.FullTime.Away,
+			},
+		}
+
+		if match.Group != nil {
+			m.Group = *match.Group
+		}
+
+		if match.Season.Id != 0 {
+			m.Season = strconv.Itoa(match.Season.Id)
+		}
+
+		for _, referee := range match.Referees {
+			m.Referees = append(m.Referees, Referee{
+				Name:        referee.Name,
+				Type:        referee.Type,
+				Nationality: referee.Nationality,
+			})
+		}
+
+		if match.Odds != nil {
+			m.Odds = Odds{
+				HomeWin: match.Odds.HomeWin,
+				Draw:    match.Odds.Draw,
+				AwayWin: match.Odds.AwayWin,
+			}
+		}
+
+		matches = append(matches, m)
+	}
+
+	return matches, nil
+}
+
+type apiStandings struct {
+	Standings []struct {
+		Stage string  `json:"stage"`
+		Type  string  `json:"type"`
+		Group *string `json:"group"`
+		Table []struct {
+			Position       int          `json:"position"`
+			Team           apiMatchTeam `json:"team"`
+			PlayedGames    int          `json:"playedGames"`
+			Form           *string      `json:"form"`
+			Won            int          `json:"won"`
+			Draw           int          `json:"draw"`
+			Lost           int          `json:"lost"`
+			Points         int          `json:"points"`
+			GoalsFor       int          `json:"goalsFor"`
+			GoalsAgainst   int          `json:"goalsAgainst"`
+			GoalDifference int          `json:"goalDifference"`
+		} `json:"table"`
+	} `json:"standings"`
+}
+
+// GetStandings returns the overall tables, home and away splits are left out
+func (f *FootballData) GetStandings(ctx context.Context, competition string) ([]Standing, error) {
+	var resp apiStandings
+	if err := f.fetch(ctx, fmt.Sprintf("/competitions/%s/standings", competition), &resp); err != nil {
+		return nil, err
+	}
+
+	var standings []Standing
+	for _, table := range resp.Standings {
+		if table.Type != "TOTAL" {
+			continue
+		}
+
+		for _, row := range table.Table {
+			standing := Standing{
+				Position:       row.Position,
+				Team:           toMatchTeam(row.Team),
+				PlayedGames:    row.PlayedGames,
+				Won:            row.Won,
+				Draw:           row.Draw,
+				Lost:           row.Lost,
+				GoalsFor:       row.GoalsFor,
+				GoalsAgainst:   row.GoalsAgainst,
+				GoalDifference: row.GoalDifference,
+				Points:         row.Points,
+			}
+			if table.Group != nil {
+				standing.Group = *table.Group
+			}
+			if row.Form != nil {
+				standing.Form = *row.Form
+			}
+			standings = append(standings, standing)
+		}
+	}
+
+	return standings, nil
+}
+
+func toMatchTeam(team apiMatchTeam) MatchTeam {
+	res := MatchTeam{LeagueRank: team.LeagueRank}
+	if team.Id != nil {
+		res.ID = strconv.Itoa(*team.Id)
+	}
+	if team.Name != nil {
+		res.Name = *team.Name
+	}
+	if team.ShortName != nil {
+		res.ShortName = *team.ShortName
+	}
+	if team.Tla != nil {
+		res.TLA = *team.Tla
+	}
+	if team.Crest != nil {
+		res.Crest = *team.Crest
+	}
+	return res
+}
+
+func footballDataStatus(status string) string {
+	switch status {
+	case "SCHEDULED", "TIMED":
+		return StatusScheduled
+	case "IN_PLAY", "PAUSED":
+		return StatusLive
+	case "FINISHED", "AWARDED":
+		return StatusFinished
+	case "POSTPONED", "SUSPENDED":
+		return StatusPostponed
+	case "CANCELLED":
+		return StatusCancelled
+	default:
+		return StatusUnknown
+	}
+}
+
+func (f *FootballData) fetch(ctx context.Context, endpoint string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s%s", f.baseURL, endpoint), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Auth-Token", f.apiKey)
+
+	resp, err := f.executeWithRateLimit(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode API response: %w", err)
+	}
+
+	return nil
+}
+
+// executeWithRateLimit waits for the shared limiter and retries throttled requests,
+// server errors and network failures with jittered backoff
+func (f *FootballData) executeWithRateLimit(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if err := f.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := f.client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if attempt >= maxRetries {
+				return nil, fmt.Errorf("failed to execute request: %w", err)
+			}
+
+			wait := backoff(attempt, retryBaseDelay)
+			log.Printf("Request to %s failed: %v. Retrying after %v...", req.URL.Path, err, wait)
+			if err := sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		f.limiter.Observe(resp.Header)
+
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			if attempt >= maxRetries {
+				return nil, fmt.Errorf("rate limited after %d retries", attempt)
+			}
+
+			// The limiter holds every job back until the quota resets
+			wait := resetAfter(resp.Header, defaultRateLimitReset)
+			log.Printf("Rate limit reached. Retrying after %v...", wait)
+			f.limiter.Block(wait)
+		case resp.StatusCode >= http.StatusInternalServerError:
+			if attempt >= maxRetries {
+				return nil, fmt.Errorf("unexpected response status after %d retries: %d", attempt, resp.StatusCode)
+			}
+
+			wait := backoff(attempt, retryBaseDelay)
+			log.Printf("Server error %d from %s. Retrying after %v...", resp.StatusCode, req.URL.Path, wait)
+			if err := sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unexpected response status: %d", resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package football
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/user/project/internal/clock"
+)
+
+// Limiter is a token bucket shared by every request to a provider, so concurrent
+// jobs draw from the same per-minute quota. The provider's quota headers
+// override the local estimate whenever they report fewer requests left.
+type Limiter struct {
+	mu       sync.Mutex
+	capacity float64
+	rate     float64 // Tokens added per second
+	tokens   float64
+	last     time.Time
+	blocked  time.Time // No requests until then, set when the quota is exhausted
+	clock    clock.Clock
+}
+
+// NewLimiter allows perMinute requests per minute, with bursts of up to perMinute
+func NewLimiter(perMinute int) *Limiter {
+	c := clock.Real()
+	return &Limiter{
+		capacity: float64(perMinute),
+		rate:     float64(perMinute) / 60,
+		tokens:   float64(perMinute),
+		last:     c.Now(),
+		clock:    c,
+	}
+}
+
+// Wait blocks until a request may be sent or ctx is done. A nil limiter never blocks.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	for {
+		wait := l.reserve()
+		if wait <= 0 {
+			return nil
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token and returns zero, or returns how long to wait before trying again
+func (l *Limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	if now.Before(l.blocked) {
+		return l.blocked.Sub(now)
+	}
+
+	// The provider's counter has reset, the whole quota is available again
+	if !l.blocked.IsZero() {
+		l.blocked = time.Time{}
+		l.tokens = l.capacity
+		l.last = now
+	}
+
+	l.tokens = min(l.capacity, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Observe adjusts the bucket to the quota reported by football-data.org:
+// X-Requests-Available-Minute is what's left and X-RequestCounter-Reset the seconds until the counter resets.
+func (l *Limiter) Observe(header http.Header) {
+	if l == nil {
+		return
+	}
+
+	available, err := strconv.Atoi(header.Get("X-Requests-Available-Minute"))
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = min(l.tokens, float64(available))
+	if available <= 0 {
+		// Without a reset time an immediate expiry would refill the bucket and burst right into the limit
+		l.blocked = l.clock.Now().Add(resetAfter(header, defaultRateLimitReset))
+	}
+}
+
+// Block stops all requests for d, used when the provider rejects a request with 429
+func (l *Limiter) Block(d time.Duration) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := l.clock.Now().Add(d); until.After(l.blocked) {
+		l.blocked = until
+	}
+}
+
+// resetAfter reads X-RequestCounter-Reset, falling back to def when it's missing or malformed
+func resetAfter(header http.Header, def time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("X-RequestCounter-Reset"))
+	if err != nil || seconds < 0 {
+		return def
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// backoff returns an exponential delay for the given attempt with ±50% jitter
+func backoff(attempt int, base time.Duration) time.Duration {
+	d := base << attempt
+	return d/2 + time.Duration(rand.Int63n(int64(d)))
+}
+
+// sleep waits for d unless ctx is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
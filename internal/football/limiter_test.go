@@ -0,0 +1,125 @@
+package football
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/user/project/internal/clock"
+)
+
+func newTestLimiter(perMinute int) (*Limiter, *clock.Manual) {
+	c := clock.NewManual(time.Date(2024, 8, 17, 12, 0, 0, 0, time.UTC))
+	l := NewLimiter(perMinute)
+	l.clock = c
+	l.last = c.Now()
+	return l, c
+}
+
+func quotaHeader(available, reset string) http.Header {
+	header := http.Header{}
+	if available != "" {
+		header.Set("X-Requests-Available-Minute", available)
+	}
+	if reset != "" {
+		header.Set("X-RequestCounter-Reset", reset)
+	}
+	return header
+}
+
+// drain takes tokens until the limiter asks to wait and returns how many it got
+func drain(l *Limiter) int {
+	taken := 0
+	for l.reserve() == 0 {
+		taken++
+	}
+	return taken
+}
+
+func TestLimiter_Reserve(t *testing.T) {
+	l, c := newTestLimiter(10)
+
+	assert.Equal(t, 10, drain(l), "the whole quota is available as a burst")
+	assert.Equal(t, 6*time.Second, l.reserve(), "one token refills every 6 seconds")
+
+	c.Advance(3 * time.Second)
+	assert.Equal(t, 3*time.Second, l.reserve())
+
+	c.Advance(3 * time.Second)
+	assert.Zero(t, l.reserve())
+	assert.Positive(t, l.reserve())
+
+	c.Advance(time.Hour)
+	assert.Equal(t, 10, drain(l), "refill never exceeds the capacity")
+}
+
+func TestLimiter_Observe(t *testing.T) {
+	t.Run("fewer requests left than estimated", func(t *testing.T) {
+		l, _ := newTestLimiter(10)
+
+		l.Observe(quotaHeader("2", "40"))
+		assert.Equal(t, 2, drain(l))
+	})
+
+	t.Run("more requests left than estimated", func(t *testing.T) {
+		l, _ := newTestLimiter(10)
+		drain(l)
+
+		l.Observe(quotaHeader("8", "40"))
+		assert.Positive(t, l.reserve(), "the local estimate is kept when it's lower")
+	})
+
+	t.Run("missing or malformed headers are ignored", func(t *testing.T) {
+		l, _ := newTestLimiter(10)
+
+		l.Observe(quotaHeader("", "40"))
+		l.Observe(quotaHeader("many", ""))
+		assert.Equal(t, 10, drain(l))
+	})
+
+	t.Run("exhausted quota blocks until the counter resets", func(t *testing.T) {
+		l, c := newTestLimiter(10)
+
+		l.Observe(quotaHeader("0", "30"))
+		assert.Equal(t, 30*time.Second, l.reserve())
+
+		c.Advance(29 * time.Second)
+		assert.Equal(t, time.Second, l.reserve())
+
+		c.Advance(time.Second)
+		assert.Equal(t, 10, drain(l), "the whole quota is back after the reset")
+	})
+
+	t.Run("exhausted quota without reset time blocks for the default", func(t *testing.T) {
+		l, c := newTestLimiter(10)
+
+		l.Observe(quotaHeader("0", ""))
+		assert.Equal(t, defaultRateLimitReset, l.reserve())
+
+		c.Advance(defaultRateLimitReset)
+		assert.Zero(t, l.reserve())
+	})
+}
+
+func TestLimiter_Block(t *testing.T) {
+	l, c := newTestLimiter(10)
+
+	l.Block(20 * time.Second)
+	assert.Equal(t, 20*time.Second, l.reserve())
+
+	l.Block(5 * time.Second)
+	assert.Equal(t, 20*time.Second, l.reserve(), "a shorter block doesn't lift a longer one")
+
+	c.Advance(20 * time.Second)
+	assert.Equal(t, 10, drain(l))
+}
+
+func TestLimiter_Nil(t *testing.T) {
+	var l *Limiter
+
+	l.Observe(quotaHeader("0", "30"))
+	l.Block(time.Minute)
+	assert.NoError(t, l.Wait(context.Background()))
+}
@@ -0,0 +1,104 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/project/internal/scheduler"
+)
+
+func TestParse_Next(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	utc := func(value string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04", value)
+		require.NoError(t, err)
+		return parsed
+	}
+	local := func(value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, berlin)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		loc  *time.Location
+		from time.Time
+		want time.Time
+	}{
+		{"every minute", "* * * * *", time.UTC, utc("2024-08-14 10:10"), utc("2024-08-14 10:11")},
+		{"strictly after", "30 10 * * *", time.UTC, utc("2024-08-14 10:30"), utc("2024-08-15 10:30")},
+		{"seconds are dropped", "* * * * *", time.UTC, utc("2024-08-14 10:10").Add(59 * time.Second), utc("2024-08-14 10:11")},
+		{"list", "0,30 * * * *", time.UTC, utc("2024-08-14 10:10"), utc("2024-08-14 10:30")},
+		{"list wraps to next hour", "0,30 * * * *", time.UTC, utc("2024-08-14 10:30"), utc("2024-08-14 11:00")},
+		{"range", "0 9-17 * * *", time.UTC, utc("2024-08-14 12:05"), utc("2024-08-14 13:00")},
+		{"range wraps to next day", "0 9-17 * * *", time.UTC, utc("2024-08-14 17:05"), utc("2024-08-15 09:00")},
+		{"step", "*/15 * * * *", time.UTC, utc("2024-08-14 10:16"), utc("2024-08-14 10:30")},
+		{"step from value", "5/20 * * * *", time.UTC, utc("2024-08-14 10:06"), utc("2024-08-14 10:25")},
+		{"step in range", "0 8-18/5 * * *", time.UTC, utc("2024-08-14 13:01"), utc("2024-08-14 18:00")},
+		{"hourly", "@hourly", time.UTC, utc("2024-08-14 10:10"), utc("2024-08-14 11:00")},
+		{"monthly", "@monthly", time.UTC, utc("2024-08-14 10:10"), utc("2024-09-01 00:00")},
+		{"month", "0 0 1 1 *", time.UTC, utc("2024-08-14 10:10"), utc("2025-01-01 00:00")},
+		{"day of week", "0 10 * * 1", time.UTC, utc("2024-08-15 10:00"), utc("2024-08-19 10:00")},
+		{"sunday as 0", "0 10 * * 0", time.UTC, utc("2024-08-14 10:00"), utc("2024-08-18 10:00")},
+		{"sunday as 7", "0 10 * * 7", time.UTC, utc("2024-08-14 10:00"), utc("2024-08-18 10:00")},
+		{"weekly", "@weekly", time.UTC, utc("2024-08-14 10:00"), utc("2024-08-18 00:00")},
+		{"day of month or day of week, weekday first", "0 0 13 * 5", time.UTC, utc("2024-08-01 00:00"), utc("2024-08-02 00:00")},
+		{"day of month or day of week, date first", "0 0 13 * 5", time.UTC, utc("2024-08-10 00:00"), utc("2024-08-13 00:00")},
+		{"leap day", "0 0 29 2 *", time.UTC, utc("2025-01-01 00:00"), utc("2028-02-29 00:00")},
+		{"impossible date", "0 0 30 2 *", time.UTC, utc("2024-01-01 00:00"), time.Time{}},
+		{"timezone", "0 10 * * *", berlin, utc("2024-08-14 07:00"), utc("2024-08-14 08:00")},
+		{"wall clock kept over spring forward", "0 10 * * *", berlin, local("2024-03-30 10:00"), local("2024-03-31 10:00")},
+		{"skipped hour doesn't fire", "30 2 * * *", berlin, local("2024-03-30 03:00"), local("2024-04-01 02:30")},
+		{"wall clock kept over fall back", "0 10 * * *", berlin, local("2024-10-26 10:00"), local("2024-10-27 10:00")},
+		{"repeated hour fires once", "30 2 * * *", berlin, utc("2024-10-27 00:30"), local("2024-10-28 02:30")},
+		{"hourly over fall back", "0 * * * *", berlin, utc("2024-10-27 00:00"), utc("2024-10-27 01:00")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := scheduler.Parse(tt.spec, tt.loc)
+			require.NoError(t, err)
+
+			next := schedule.Next(tt.from)
+			assert.True(t, tt.want.Equal(next), "want %s, got %s", tt.want, next)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every",
+		"@every -5m",
+		"@yearly",
+	}
+
+	for _, spec := range specs {
+		_, err := scheduler.Parse(spec, time.UTC)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestEvery_Next(t *testing.T) {
+	schedule, err := scheduler.Parse("@every 5m", time.UTC)
+	require.NoError(t, err)
+
+	from := time.Date(2024, 8, 14, 10, 10, 30, 0, time.UTC)
+	assert.Equal(t, from.Add(5*time.Minute), schedule.Next(from))
+}
@@ -0,0 +1,355 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/user/project/internal/clock"
+)
+
+var (
+	ErrUnknownJob     = errors.New("unknown job")
+	ErrNotLeader      = errors.New("this instance is not the scheduler leader")
+	ErrAlreadyRunning = errors.New("job is already running")
+	ErrNotStarted     = errors.New("scheduler is not started")
+	ErrStopped        = errors.New("scheduler is stopped")
+)
+
+// leaderLease is the lease name replicas compete for
+const leaderLease = "scheduler"
+
+type leaser interface {
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration, now time.Time) (bool, error)
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
+// Job is a unit of background work
+type Job struct {
+	Name       string
+	Schedule   Schedule
+	Run        func(ctx context.Context) error
+	RunOnStart bool // Run as soon as this instance becomes the leader, e.g. to catch up after a restart
+}
+
+// JobInfo describes a registered job for the admin API
+type JobInfo struct {
+	Name    string     `json:"name"`
+	Running bool       `json:"running"`
+	NextRun *time.Time `json:"next_run"`
+	LastRun *time.Time `json:"last_run"`
+	LastErr *string    `json:"last_error"`
+}
+
+type Config struct {
+	Holder   string        // Identifies this replica in the lease, e.g. hostname and pid
+	LeaseTTL time.Duration // How long leadership survives without renewal, 30 seconds by default
+	Clock    clock.Clock
+}
+
+// Scheduler runs registered jobs on their schedules. Replicas share a database lease
+// so only the leader runs jobs; a job never overlaps with itself and a panic in one
+// is logged instead of taking the process down.
+type Scheduler struct {
+	lease leaser
+	cfg   Config
+
+	mu      sync.Mutex
+	jobs    map[string]*entry
+	leader  bool
+	stopped bool            // Set once Start begins waiting for runs, no new ones may launch
+	ctx     context.Context // Set by Start
+	wg      sync.WaitGroup
+
+	// Runs live only as long as this instance leads, so a replica that loses
+	// the lease stops its jobs before the new leader starts them again
+	leadCtx    context.Context
+	leadCancel context.CancelFunc
+}
+
+type entry struct {
+	job     Job
+	running bool
+	nextRun time.Time
+	lastRun time.Time
+	lastErr error
+}
+
+func New(lease leaser, cfg Config) *Scheduler {
+	if cfg.LeaseTTL == 0 {
+		cfg.LeaseTTL = 30 * time.Second
+	}
+
+	if cfg.Clock == nil {
+		cfg.Clock = clock.Real()
+	}
+
+	return &Scheduler{
+		lease: lease,
+		cfg:   cfg,
+		jobs:  make(map[string]*entry),
+	}
+}
+
+// Register adds a job. Jobs must be registered before Start.
+func (s *Scheduler) Register(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[job.Name] = &entry{job: job}
+}
+
+// Start runs the scheduler until ctx is done, then waits for running jobs to return
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	renew := time.NewTicker(s.cfg.LeaseTTL / 3)
+	defer renew.Stop()
+
+	s.renewLeadership(ctx)
+
+	for {
+		wait := s.dispatchDue()
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-timer.C:
+		case <-renew.C:
+			s.renewLeadership(ctx)
+		case <-ctx.Done():
+			timer.Stop()
+
+			s.mu.Lock()
+			s.stopped = true
+			s.mu.Unlock()
+
+			s.wg.Wait()
+			s.resign()
+			return
+		}
+
+		timer.Stop()
+	}
+}
+
+// RunNow starts a job immediately, outside its schedule
+func (s *Scheduler) RunNow(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownJob, name)
+	}
+	if s.ctx == nil {
+		return ErrNotStarted
+	}
+	if s.stopped {
+		return ErrStopped
+	}
+	if !s.leader {
+		return ErrNotLeader
+	}
+	if e.running {
+		return fmt.Errorf("%w: %s", ErrAlreadyRunning, name)
+	}
+
+	s.launch(e)
+	return nil
+}
+
+// Jobs lists registered jobs by name
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]JobInfo, 0, len(s.jobs))
+	for _, e := range s.jobs {
+		info := JobInfo{Name: e.job.Name, Running: e.running}
+		if !e.nextRun.IsZero() {
+			next := e.nextRun
+			info.NextRun = &next
+		}
+		if !e.lastRun.IsZero() {
+			last := e.lastRun
+			info.LastRun = &last
+		}
+		if e.lastErr != nil {
+			msg := e.lastErr.Error()
+			info.LastErr = &msg
+		}
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// IsLeader reports whether this instance currently runs jobs
+func (s *Scheduler) IsLeader() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.leader
+}
+
+// dispatchDue launches the jobs that are due and returns how long to sleep until the next one
+func (s *Scheduler) dispatchDue() time.Duration {
+	now := s.cfg.Clock.Now()
+	wait := s.cfg.LeaseTTL / 3
+
+	s.mu.Lock()
+	var due []*entry
+	for _, e := range s.jobs {
+		if e.nextRun.IsZero() {
+			continue
+		}
+
+		if e.nextRun.After(now) {
+			wait = min(wait, e.nextRun.Sub(now))
+			continue
+		}
+
+		if s.leader {
+			if e.running {
+				log.Printf("Job %s is still running, skipping this run", e.job.Name)
+			} else {
+				s.launch(e)
+			}
+		}
+		due = append(due, e)
+	}
+	s.mu.Unlock()
+
+	for _, next := range s.reschedule(due, now) {
+		if !next.IsZero() {
+			wait = min(wait, next.Sub(now))
+		}
+	}
+
+	return max(wait, 0)
+}
+
+// reschedule sets the next run of the entries after now. Schedules may query the
+// database, so they are evaluated without holding s.mu; only the Start loop
+// changes nextRun, nothing else can move it meanwhile.
+func (s *Scheduler) reschedule(entries []*entry, now time.Time) []time.Time {
+	next := make([]time.Time, len(entries))
+	for i, e := range entries {
+		next[i] = e.job.Schedule.Next(now)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, e := range entries {
+		e.nextRun = next[i]
+	}
+
+	return next
+}
+
+// renewLeadership takes or extends the lease; a new leader schedules every job from now
+func (s *Scheduler) renewLeadership(ctx context.Context) {
+	ok, err := s.lease.AcquireLease(ctx, leaderLease, s.cfg.Holder, s.cfg.LeaseTTL, s.cfg.Clock.Now())
+	if err != nil {
+		log.Printf("Failed to renew scheduler lease: %v", err)
+		ok = false
+	}
+
+	s.mu.Lock()
+	if ok == s.leader {
+		s.mu.Unlock()
+		return
+	}
+	s.leader = ok
+
+	if !ok {
+		log.Printf("Scheduler %s lost leadership", s.cfg.Holder)
+		s.leadCancel()
+		for _, e := range s.jobs {
+			e.nextRun = time.Time{}
+		}
+		s.mu.Unlock()
+		return
+	}
+
+	log.Printf("Scheduler %s became the leader", s.cfg.Holder)
+	s.leadCtx, s.leadCancel = context.WithCancel(ctx)
+	now := s.cfg.Clock.Now()
+	var scheduled []*entry
+	for _, e := range s.jobs {
+		if e.job.RunOnStart {
+			e.nextRun = now
+		} else {
+			scheduled = append(scheduled, e)
+		}
+	}
+	s.mu.Unlock()
+
+	s.reschedule(scheduled, now)
+}
+
+func (s *Scheduler) resign() {
+	s.mu.Lock()
+	leader := s.leader
+	s.leader = false
+	if leader {
+		s.leadCancel()
+	}
+	s.mu.Unlock()
+
+	if !leader {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.lease.ReleaseLease(ctx, leaderLease, s.cfg.Holder); err != nil {
+		log.Printf("Failed to release scheduler lease: %v", err)
+	}
+}
+
+// launch runs the job in the background until it returns or leadership ends. Callers hold s.mu.
+func (s *Scheduler) launch(e *entry) {
+	ctx := s.leadCtx
+
+	e.running = true
+	e.lastRun = s.cfg.Clock.Now()
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		err := s.run(ctx, e.job)
+
+		s.mu.Lock()
+		e.running = false
+		e.lastErr = err
+		s.mu.Unlock()
+	}()
+}
+
+// run calls the job, turning a panic into an error
+func (s *Scheduler) run(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+			log.Printf("Job %s panicked: %v\n%s", job.Name, r, debug.Stack())
+		}
+	}()
+
+	if err := job.Run(ctx); err != nil {
+		log.Printf("Job %s failed: %v", job.Name, err)
+		return err
+	}
+
+	return nil
+}
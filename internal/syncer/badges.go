@@ -0,0 +1,169 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	telegram "github.com/go-telegram/bot"
+	"github.com/user/project/internal/contract"
+	"github.com/user/project/internal/db"
+)
+
+// settlement describes a prediction that has just been scored
+type settlement struct {
+	User       db.User // with streaks and counters already updated
+	Match      db.Match
+	BasePoints int
+}
+
+type settlementRule struct {
+	BadgeID string
+	Earned  func(ctx context.Context, s *Syncer, e settlement) (bool, error)
+}
+
+var settlementRules = []settlementRule{
+	{db.BadgeFirstExactScore, func(_ context.Context, _ *Syncer, e settlement) (bool, error) {
+		return e.BasePoints == 7, nil
+	}},
+	{db.BadgeWinStreak5, streakRule(db.BadgeWinStreakLengths[db.BadgeWinStreak5])},
+	{db.BadgeWinStreak10, streakRule(db.BadgeWinStreakLengths[db.BadgeWinStreak10])},
+	{db.BadgeWinStreak20, streakRule(db.BadgeWinStreakLengths[db.BadgeWinStreak20])},
+	{db.BadgePredictions100, func(_ context.Context, _ *Syncer, e settlement) (bool, error) {
+		return e.User.TotalPredictions >= db.BadgePredictionsTarget, nil
+	}},
+	{db.BadgeFullMatchday, func(ctx context.Context, s *Syncer, e settlement) (bool, error) {
+		return s.predictedFullMatchday(ctx, e.User.ID, e.Match)
+	}},
+}
+
+func streakRule(length int) func(context.Context, *Syncer, settlement) (bool, error) {
+	return func(_ context.Context, _ *Syncer, e settlement) (bool, error) {
+		return e.User.CurrentWinStreak >= length, nil
+	}
+}
+
+// predictedFullMatchday checks whether the user predicted every match of the
+// given match's matchday in its season. Matches outside league rounds, e.g. cup knockouts, never count.
+func (s *Syncer) predictedFullMatchday(ctx context.Context, userID string, match db.Match) (bool, error) {
+	if match.CompetitionCode == "" || match.Matchday == nil {
+		return false, nil
+	}
+
+	total, predicted, err := s.storage.CountMatchdayPredictions(ctx, userID, match.CompetitionCode, match.CompetitionSeason, *match.Matchday)
+	if err != nil {
+		return false, err
+	}
+
+	return total >= db.MinMatchdayMatches && predicted == total, nil
+}
+
+// evaluateSettlementBadges runs every settlement rule the user hasn't satisfied yet
+// and returns the badges awarded for the first time.
+func (s *Syncer) evaluateSettlementBadges(ctx context.Context, e settlement) []db.Badge {
+	var awarded []db.Badge
+	for _, rule := range settlementRules {
+		if hasBadge(e.User, rule.BadgeID) {
+			continue
+		}
+
+		earned, err := rule.Earned(ctx, s, e)
+		if err != nil {
+			log.Printf("Failed to evaluate badge %s for user %s: %v", rule.BadgeID, e.User.ID, err)
+			continue
+		}
+
+		if !earned {
+			continue
+		}
+
+		if badge, ok := s.awardBadge(ctx, e.User, rule.BadgeID); ok {
+			awarded = append(awarded, badge)
+		}
+	}
+
+	return awarded
+}
+
+// AwardSeasonBadges gives the top 10 badge to everyone who finished the season in the top 10.
+func (s *Syncer) AwardSeasonBadges(ctx context.Context, season db.Season) error {
+	leaderboard, err := s.storage.GetLeaderboard(ctx, season.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get leaderboard for season %s: %w", season.ID, err)
+	}
+
+	var notifications []notification
+	for _, entry := range leaderboard {
+		if entry.Position > db.BadgeSeasonTopPosition {
+			break
+		}
+
+		user, err := s.storage.GetUserByID(entry.UserID)
+		if err != nil {
+			log.Printf("Failed to fetch user %s: %v", entry.UserID, err)
+			continue
+		}
+
+		if hasBadge(user, db.BadgeSeasonTop10) {
+			continue
+		}
+
+		if badge, ok := s.awardBadge(ctx, user, db.BadgeSeasonTop10); ok {
+			notifications = append(notifications, s.badgeNotification(user, badge))
+		}
+	}
+
+	s.sendNotifications(ctx, notifications)
+	return nil
+}
+
+// awardBadge stores the badge and returns it the first time it is earned, for the user to be notified.
+func (s *Syncer) awardBadge(ctx context.Context, user db.User, badgeID string) (db.Badge, bool) {
+	awarded, err := s.storage.AwardBadge(ctx, user.ID, badgeID)
+	if err != nil {
+		log.Printf("Failed to award badge %s to user %s: %v", badgeID, user.ID, err)
+		return db.Badge{}, false
+	}
+
+	if !awarded {
+		return db.Badge{}, false
+	}
+
+	log.Printf("Awarded badge %s to user %s", badgeID, user.ID)
+
+	badge, err := s.storage.GetBadgeByID(ctx, badgeID)
+	if err != nil {
+		log.Printf("Failed to fetch badge %s: %v", badgeID, err)
+		return db.Badge{}, false
+	}
+
+	return badge, true
+}
+
+func (s *Syncer) badgeNotification(user db.User, badge db.Badge) notification {
+	message := fmt.Sprintf("%s Новое достижение: «%s»! Загляни в профиль, чтобы посмотреть все свои награды.", badge.Icon, badge.Name)
+	buttonText := "Открыть профиль"
+	if user.LanguageCode != nil && *user.LanguageCode != "ru" {
+		message = fmt.Sprintf("%s New badge unlocked: \"%s\"! Check your profile to see all your achievements.", badge.Icon, badge.Name)
+		buttonText = "Open profile"
+	}
+
+	return notification{
+		User: user,
+		Kind: db.NotificationResults,
+		Params: contract.SendNotificationParams{
+			Message:    telegram.EscapeMarkdown(message),
+			WebAppURL:  fmt.Sprintf("%s/users/%s", s.cfg.WebAppURL, user.Username),
+			ButtonText: buttonText,
+		},
+	}
+}
+
+func hasBadge(user db.User, badgeID string) bool {
+	for _, badge := range user.Badges {
+		if badge.ID == badgeID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/user/project/internal/contract"
+	"github.com/user/project/internal/db"
+)
+
+// notification is a message prepared now and delivered later, e.g. once a lock is released
+type notification struct {
+	User   db.User
+	Kind   string
+	Params contract.SendNotificationParams
+}
+
+// sendNotifications delivers each notification, a failed one doesn't stop the rest
+func (s *Syncer) sendNotifications(ctx context.Context, notifications []notification) {
+	for _, n := range notifications {
+		if _, err := s.deliver(ctx, n.User, n.Kind, n.Params); err != nil {
+			log.Printf("Failed to send %s notification to user %s: %v", n.Kind, n.User.ID, err)
+		}
+	}
+}
+
+// deliver hands a notification of the given kind to the notifier unless the user
+// turned that kind off. During the user's quiet hours it is queued until they end.
+// Every message meant for a user goes through here; it reports whether the message
+// was sent or queued, either way callers must not send it again.
+func (s *Syncer) deliver(ctx context.Context, user db.User, kind string, params contract.SendNotificationParams) (bool, error) {
+	return s.deliverBefore(ctx, user, kind, params, time.Time{})
+}
+
+// Deliver is deliver for messages sent from outside the syncer, like the API's broadcasts
+func (s *Syncer) Deliver(ctx context.Context, user db.User, kind string, params contract.SendNotificationParams) (bool, error) {
+	return s.deliver(ctx, user, kind, params)
+}
+
+// deliverBefore is deliver for messages that are pointless after the deadline, like
+// reminders about a match that has kicked off. They are dropped instead of sent late.
+func (s *Syncer) deliverBefore(ctx context.Context, user db.User, kind string, params contract.SendNotificationParams, deadline time.Time) (bool, error) {
+	settings, err := s.storage.GetNotificationSettings(ctx, user.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+
+	if !settings.Enabled(kind) {
+		log.Printf("User %s turned off %s notifications", user.ID, kind)
+		return false, nil
+	}
+
+	now := s.now()
+	if !deadline.IsZero() && !now.Before(deadline) {
+		return false, nil
+	}
+
+	if end := settings.QuietUntil(now, s.cfg.Location); !end.IsZero() {
+		if !deadline.IsZero() && !end.Before(deadline) {
+			log.Printf("Skipping %s notification for user %s, quiet hours last past %s", kind, user.ID, deadline.Format(time.RFC3339))
+			return false, nil
+		}
+
+		queued := db.QueuedNotification{
+			User:         user,
+			Kind:         kind,
+			Message:      params.Message,
+			WebAppURL:    params.WebAppURL,
+			ButtonText:   params.ButtonText,
+			Image:        params.Image,
+			DeliverAfter: end,
+		}
+		if !deadline.IsZero() {
+			queued.ExpiresAt = &deadline
+		}
+
+		if err := s.storage.QueueNotification(ctx, queued); err != nil {
+			return false, fmt.Errorf("failed to queue notification: %w", err)
+		}
+
+		log.Printf("Queued %s notification for user %s until %s", kind, user.ID, end.Format(time.RFC3339))
+		return true, nil
+	}
+
+	params.ChatID = user.ChatID
+	if params.Image != nil {
+		err = s.notifier.SendPhotoNotification(params)
+	} else {
+		err = s.notifier.SendTextNotification(params)
+	}
+
+	return err == nil, err
+}
+
+// SendQueuedNotifications delivers the notifications held back by quiet hours that are over.
+// Settings are checked again, the user may have turned the kind off or moved their quiet hours.
+func (s *Syncer) SendQueuedNotifications(ctx context.Context) (err error) {
+	run := s.startRun(ctx, JobQueuedNotifications)
+	defer func() { s.finishRun(ctx, run, err) }()
+
+	due, err := s.storage.GetDueNotifications(ctx, s.now())
+	if err != nil {
+		return fmt.Errorf("failed to get queued notifications: %w", err)
+	}
+
+	for _, n := range due {
+		// Removed first: a notification that fails to send is lost rather than repeated on every run
+		if err := s.storage.DeleteQueuedNotification(ctx, n.ID); err != nil {
+			return fmt.Errorf("failed to dequeue notification %s: %w", n.ID, err)
+		}
+
+		var deadline time.Time
+		if n.ExpiresAt != nil {
+			deadline = *n.ExpiresAt
+		}
+
+		_, err := s.deliverBefore(ctx, n.User, n.Kind, contract.SendNotificationParams{
+			Message:    n.Message,
+			WebAppURL:  n.WebAppURL,
+			ButtonText: n.ButtonText,
+			Image:      n.Image,
+		}, deadline)
+		if err != nil {
+			log.Printf("Failed to send queued %s notification to user %s: %v", n.Kind, n.User.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/user/project/internal/db"
+	"log"
+)
+
+// SnapshotLeaderboards stores today's rank of every user in each active season,
+// so the leaderboard can show how positions moved since the previous day.
+func (s *Syncer) SnapshotLeaderboards(ctx context.Context) error {
+	seasons, err := s.storage.GetActiveSeasons(ctx)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return fmt.Errorf("failed to get active seasons: %w", err)
+	}
+
+	today := s.now()
+	for _, season := range seasons {
+		if err := s.storage.SnapshotLeaderboard(ctx, season.ID, today); err != nil {
+			log.Printf("Failed to snapshot leaderboard for season %s: %v", season.ID, err)
+			continue
+		}
+
+		log.Printf("Leaderboard snapshot saved for season %s (%s)", season.Name, today.Format("2006-01-02"))
+	}
+
+	return nil
+}
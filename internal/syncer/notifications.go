@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-telegram/bot"
+	"github.com/user/project/internal/clock"
 	"github.com/user/project/internal/db"
 	"io"
 	"log"
@@ -38,18 +40,33 @@ func generateMatchReminderText(user db.User, homeTeam db.Team, awayTeam db.Team)
 	return messages["en"]
 }
 
-func (s *Syncer) SendMatchNotification(ctx context.Context) error {
+func (s *Syncer) SendMatchNotification(ctx context.Context) (err error) {
+	run := s.startRun(ctx, JobNotifications)
+	defer func() { s.finishRun(ctx, run, err) }()
+
 	users, err := s.storage.GetAllUsersWithFavoriteTeam(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to fetch users with favorite teams: %w", err)
 	}
 
+	now := s.now()
+
 	for _, user := range users {
 		if user.FavoriteTeamID == nil {
 			continue
 		}
 
-		matches, err := s.storage.GetMatchesForTeam(ctx, *user.FavoriteTeamID, 14)
+		// Checked before any lookups and the preview rendering, deliverBefore would drop the message anyway
+		settings, err := s.storage.GetNotificationSettings(ctx, user.ID)
+		if err != nil {
+			log.Printf("Failed to get notification settings for user %s: %v", user.ID, err)
+			continue
+		}
+		if !settings.Enabled(db.NotificationFavoriteTeam) {
+			continue
+		}
+
+		matches, err := s.storage.GetMatchesForTeam(ctx, *user.FavoriteTeamID, now, now.Add(14*time.Hour))
 		if err != nil {
 			log.Printf("Failed to fetch matches for user %s: %v", user.ID, err)
 			continue
@@ -77,29 +94,33 @@ func (s *Syncer) SendMatchNotification(ctx context.Context) error {
 				continue
 			}
 
-			err = s.notifier.SendPhotoNotification(contract.SendNotificationParams{
+			sent, err := s.deliverBefore(ctx, user, db.NotificationFavoriteTeam, contract.SendNotificationParams{
 				Image:      imgData,
-				ChatID:     user.ChatID,
 				Message:    bot.EscapeMarkdown(generateMatchReminderText(user, homeTeam, awayTeam)),
 				WebAppURL:  fmt.Sprintf("%s/matches/%s", s.cfg.WebAppURL, match.ID),
 				ButtonText: "Make your prediction",
-			})
+			}, match.MatchDate)
 
-			if err == nil {
+			if err == nil && sent {
 				err := s.storage.LogNotification(ctx, user.ID, "match", match.ID)
 				if err != nil {
 					return err
 				}
 
 				log.Printf("Sent notification to user %s about match %s", user.ID, match.ID)
-			} else {
+			} else if err != nil {
 				log.Printf("Failed to send notification to user %s: %v", user.ID, err)
 			}
 		}
 	}
 
 	// special notification for chanel about the most popular match
-	mostPopularMatch, err := s.storage.GetTodayMostPopularMatch(ctx)
+	endOfDay := clock.StartOfDay(now, s.cfg.Location).AddDate(0, 0, 1)
+	mostPopularMatch, err := s.storage.GetTodayMostPopularMatch(ctx, now, endOfDay)
+	if errors.Is(err, db.ErrNotFound) {
+		log.Printf("No matches left today, nothing to post to the channel")
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to fetch most popular match: %w", err)
 	}
@@ -116,7 +137,7 @@ func (s *Syncer) SendMatchNotification(ctx context.Context) error {
 		log.Printf("Failed to fetch preview image for most popular match: %v", err)
 	}
 
-	text := fmt.Sprintf("%s vs %s сегодня в %s, не забудьте сделать прогноз!", mostPopularMatch.HomeTeam.ShortName, mostPopularMatch.AwayTeam.ShortName, mostPopularMatch.MatchDate.Format("15:04"))
+	text := fmt.Sprintf("%s vs %s сегодня в %s, не забудьте сделать прогноз!", mostPopularMatch.HomeTeam.ShortName, mostPopularMatch.AwayTeam.ShortName, mostPopularMatch.MatchDate.In(s.cfg.Location).Format("15:04"))
 	if err = s.notifier.SendPhotoNotification(contract.SendNotificationParams{
 		Image:      imgData,
 		ChatID:     s.cfg.ChannelChatID,
@@ -166,9 +187,10 @@ func (s *Syncer) SendWeeklyRecap(ctx context.Context) error {
 		return fmt.Errorf("failed to fetch users: %w", err)
 	}
 
-	year, week := time.Now().AddDate(0, 0, -7).ISOWeek() // Смотрим прошлую неделю
+	weekAgo := s.now().AddDate(0, 0, -7)
+	year, week := weekAgo.ISOWeek() // Смотрим прошлую неделю
 	weekNum := fmt.Sprintf("%d-W%d", year, week)
-	startOfWeek := time.Now().AddDate(0, 0, -7).Truncate(24 * time.Hour)
+	startOfWeek := clock.StartOfDay(weekAgo, s.cfg.Location)
 	endOfWeek := startOfWeek.AddDate(0, 0, 7)
 
 	for _, user := range users {
@@ -259,20 +281,19 @@ func (s *Syncer) SendWeeklyRecap(ctx context.Context) error {
 		}
 
 		// Отправляем уведомление
-		err = s.notifier.SendPhotoNotification(contract.SendNotificationParams{
+		sent, err := s.deliver(ctx, user, db.NotificationRecaps, contract.SendNotificationParams{
 			Image:      imgData,
-			ChatID:     user.ChatID,
 			Message:    bot.EscapeMarkdown(message),
 			WebAppURL:  fmt.Sprintf("%s/weekly-recap?week=%s", s.cfg.WebAppURL, weekNum),
 			ButtonText: buttonText,
 		})
-		if err == nil {
+		if err == nil && sent {
 			err := s.storage.LogNotification(ctx, user.ID, "recap", weekNum)
 			if err != nil {
 				log.Printf("Failed to log notification for user %s: %v", user.ID, err)
 			}
 			log.Printf("Sent weekly recap to user %s for week %s", user.ID, weekNum)
-		} else {
+		} else if err != nil {
 			log.Printf("Failed to send recap to user %s: %v", user.ID, err)
 		}
 	}
@@ -0,0 +1,51 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"github.com/user/project/internal/db"
+	"time"
+)
+
+const (
+	// livePollInterval is used while a match is in play or about to kick off.
+	// The provider's rate limit still spaces the requests within a poll.
+	livePollInterval = time.Minute
+	// idlePollInterval is the longest the syncer sleeps when nothing is going on
+	idlePollInterval = 30 * time.Minute
+	// kickoffLead is how long before kickoff polling turns live
+	kickoffLead = 15 * time.Minute
+	// matchOverrun is how long after kickoff a match may still be in play
+	matchOverrun = 3 * time.Hour
+)
+
+// NextPoll tells how long to wait before the next match sync and whether it should be a live one
+func (s *Syncer) NextPoll(ctx context.Context) (time.Duration, bool, error) {
+	now := s.now()
+
+	live, err := s.storage.CountLiveMatches(ctx, now, kickoffLead, matchOverrun)
+	if err != nil {
+		return livePollInterval, false, err
+	}
+
+	if live > 0 {
+		return livePollInterval, true, nil
+	}
+
+	kickoff, err := s.storage.GetNextKickoff(ctx, now)
+	if err != nil && errors.Is(err, db.ErrNotFound) {
+		return idlePollInterval, false, nil
+	} else if err != nil {
+		return livePollInterval, false, err
+	}
+
+	// Wake up when the next match is about to start
+	wait := kickoff.Sub(now) - kickoffLead
+	if wait < livePollInterval {
+		wait = livePollInterval
+	} else if wait > idlePollInterval {
+		wait = idlePollInterval
+	}
+
+	return wait, false, nil
+}
@@ -0,0 +1,172 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/user/project/internal/db"
+	"github.com/user/project/internal/football"
+)
+
+const (
+	popularityWindow = 7 * 24 * time.Hour // How far ahead RefreshPopularity rescores matches
+
+	bigClubBonus     = 100 // Per team from popularTeams
+	predictionWeight = 10  // Per prediction made on the match
+	fanWeight        = 20  // Per user whose favorite team plays
+)
+
+// popularTeams are keyed by TLA as reported by the provider and stored as team abbreviation
+var popularTeams = map[string]bool{
+	"FCB": true,
+	"ATL": true,
+	"ATH": true,
+	"RMA": true,
+	"INT": true,
+	"NAP": true,
+	"ATA": true,
+	"JUV": true,
+	"LAZ": true,
+	"ROM": true,
+	"MIL": true,
+	"B04": true,
+	"BVB": true,
+	"PSG": true,
+	"MCI": true,
+	"MUN": true,
+	"TOT": true,
+	"CHE": true,
+	"LIV": true,
+	"ARS": true,
+}
+
+// popularityFactors is everything a popularity score is built from
+type popularityFactors struct {
+	HomeRank    *int
+	AwayRank    *int
+	HomeOdds    *float64
+	AwayOdds    *float64
+	Kickoff     time.Time
+	HomeTLA     string
+	AwayTLA     string
+	Predictions int
+	Fans        int
+}
+
+// ComputePopularityScore gives a freshly synced match its initial score, before anyone engaged with it
+func ComputePopularityScore(match football.Match) float64 {
+	return popularityFactors{
+		HomeRank: match.HomeTeam.LeagueRank,
+		AwayRank: match.AwayTeam.LeagueRank,
+		HomeOdds: match.Odds.HomeWin,
+		AwayOdds: match.Odds.AwayWin,
+		Kickoff:  match.UTCDate,
+		HomeTLA:  match.HomeTeam.TLA,
+		AwayTLA:  match.AwayTeam.TLA,
+	}.score()
+}
+
+func (f popularityFactors) score() float64 {
+	// 1. Team Ranking Score (lower rank is better, so we invert)
+	rankScore := 100 - getLeagueRankScore(f.HomeRank) - getLeagueRankScore(f.AwayRank)
+
+	// 2. Odds Competitiveness Score (closer odds = more competitive = higher score)
+	oddsScore := 50 - getOddsSpread(football.Odds{HomeWin: f.HomeOdds, AwayWin: f.AwayOdds})
+
+	// 3. Match Timing Bonus (later matches get extra points)
+	timeBonus := getTimeBonus(f.Kickoff)
+
+	// 4. Popularity Bonus (if either team is in the popular list)
+	popularityBonus := getPopularityBonus(f.HomeTLA, f.AwayTLA)
+
+	// 5. Engagement of our own users
+	engagement := predictionWeight*f.Predictions + fanWeight*f.Fans
+
+	return float64(rankScore) + oddsScore + float64(timeBonus) + float64(popularityBonus) + float64(engagement)
+}
+
+// RefreshPopularity rescores upcoming matches from predictions, fans and current league positions
+func (s *Syncer) RefreshPopularity(ctx context.Context) (err error) {
+	run := s.startRun(ctx, JobPopularity)
+	defer func() { s.finishRun(ctx, run, err) }()
+
+	now := s.now()
+
+	inputs, err := s.storage.GetMatchPopularityInputs(ctx, now, now.Add(popularityWindow))
+	if err != nil {
+		return fmt.Errorf("failed to get match popularity inputs: %w", err)
+	}
+
+	scores := make(map[string]float64, len(inputs))
+	for _, in := range inputs {
+		scores[in.MatchID] = popularityFromInputs(in)
+	}
+
+	if err := s.storage.UpdateMatchPopularity(ctx, scores); err != nil {
+		return fmt.Errorf("failed to update match popularity: %w", err)
+	}
+
+	log.Printf("Refreshed popularity of %d matches", len(scores))
+
+	return nil
+}
+
+func popularityFromInputs(in db.MatchPopularityInputs) float64 {
+	return popularityFactors{
+		HomeRank:    in.HomeTeamPosition,
+		AwayRank:    in.AwayTeamPosition,
+		HomeOdds:    in.HomeOdds,
+		AwayOdds:    in.AwayOdds,
+		Kickoff:     in.MatchDate,
+		HomeTLA:     in.HomeTeamTLA,
+		AwayTLA:     in.AwayTeamTLA,
+		Predictions: in.Predictions,
+		Fans:        in.Fans,
+	}.score()
+}
+
+// getPopularityBonus checks if a team is in the popular list and assigns extra points
+func getPopularityBonus(homeTLA, awayTLA string) int {
+	bonus := 0
+	if popularTeams[homeTLA] {
+		bonus += bigClubBonus
+	}
+
+	if popularTeams[awayTLA] {
+		bonus += bigClubBonus
+	}
+
+	return bonus
+}
+
+// getLeagueRankScore handles cases where leagueRank is nil
+func getLeagueRankScore(rank *int) int {
+	if rank == nil {
+		return 50 // Assign a mid-value if no rank is available
+	}
+	return *rank
+}
+
+// getOddsSpread calculates the odds spread or assigns a high default if odds are missing
+func getOddsSpread(odds football.Odds) float64 {
+	if odds.HomeWin == nil || odds.AwayWin == nil {
+		return 50.0 // High value to deprioritize matches without odds
+	}
+	return math.Abs(*odds.HomeWin - *odds.AwayWin)
+}
+
+// getTimeBonus gives extra points for prime-time matches
+func getTimeBonus(utcDate time.Time) int {
+	hour := utcDate.Hour()
+
+	// Assign bonuses based on match timing
+	if hour >= 19 {
+		return 20 // Prime-time evening matches
+	} else if hour >= 16 {
+		return 10 // Late afternoon matches
+	}
+	return 0 // Earlier matches get no bonus
+}
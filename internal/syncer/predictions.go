@@ -11,17 +11,32 @@ import (
 	"github.com/user/project/internal/db"
 )
 
+// ProcessPredictions scores the predictions of completed matches. Badge and streak
+// notifications go out once settlement is over, slow deliveries don't hold other runs back.
 func (s *Syncer) ProcessPredictions(ctx context.Context) error {
+	notifications, err := s.settlePredictions(ctx)
+	s.sendNotifications(ctx, notifications)
+	return err
+}
+
+// settlePredictions does the scoring under settleMu and returns the notifications it earned
+func (s *Syncer) settlePredictions(ctx context.Context) (notifications []notification, err error) {
+	s.settleMu.Lock()
+	defer s.settleMu.Unlock()
+
+	run := s.startRun(ctx, JobSettlement)
+	defer func() { s.finishRun(ctx, run, err) }()
+
 	matches, err := s.storage.GetCompletedMatchesWithoutCompletedPredictions(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get completed matches: %w", err)
+		return nil, fmt.Errorf("failed to get completed matches: %w", err)
 	}
 
-	seasons, err := s.storage.GetActiveSeasons(ctx)
+	activeSeasons, err := s.storage.GetActiveSeasons(ctx)
 	if err != nil && !errors.Is(err, db.ErrNotFound) {
-		return fmt.Errorf("failed to get active season: %w", err)
+		return nil, fmt.Errorf("failed to get active season: %w", err)
 	} else if errors.Is(err, db.ErrNotFound) {
-		return fmt.Errorf("no active season found")
+		return nil, fmt.Errorf("no active season found")
 	}
 
 	for _, match := range matches {
@@ -31,6 +46,17 @@ func (s *Syncer) ProcessPredictions(ctx context.Context) error {
 			continue
 		}
 
+		// Points go to the seasons the match was played in, not the ones active at settlement,
+		// so a late match on the last day of the month still counts for that month
+		seasons, err := s.storage.GetSeasonsForDate(ctx, match.MatchDate)
+		if err != nil {
+			log.Printf("Failed to fetch seasons for match %s: %v", match.ID, err)
+			continue
+		}
+		if len(seasons) == 0 {
+			seasons = activeSeasons
+		}
+
 		for _, prediction := range predictions {
 			if match.AwayScore == nil || match.HomeScore == nil {
 				log.Printf("Skipping prediction for match %s with missing scores", match.ID)
@@ -66,6 +92,7 @@ func (s *Syncer) ProcessPredictions(ctx context.Context) error {
 				log.Printf("Failed to update prediction result for match %s, user %s: %v", prediction.MatchID, prediction.UserID, err)
 				continue
 			}
+			run.PredictionsSettled++
 
 			for _, season := range seasons {
 				err = s.storage.UpdateUserLeaderboardPoints(ctx, prediction.UserID, season.ID, totalPoints)
@@ -87,10 +114,21 @@ func (s *Syncer) ProcessPredictions(ctx context.Context) error {
 				continue
 			}
 
-			go s.notifyUser(ctx, user, user.CurrentWinStreak, bonusPoints)
+			user.TotalPredictions += 1
+			if isCorrect {
+				user.CorrectPredictions += 1
+			}
+
+			for _, badge := range s.evaluateSettlementBadges(ctx, settlement{User: user, Match: match, BasePoints: basePoints}) {
+				notifications = append(notifications, s.badgeNotification(user, badge))
+			}
+
+			if n, ok := streakNotification(user, user.CurrentWinStreak, bonusPoints); ok {
+				notifications = append(notifications, n)
+			}
 		}
 	}
-	return nil
+	return notifications, nil
 }
 
 func calculateBonus(currentStreak int) int {
@@ -134,9 +172,9 @@ func calculateBasePoints(match db.Match, prediction db.Prediction) int {
 	return 0
 }
 
-func (s *Syncer) notifyUser(ctx context.Context, user db.User, streak int, bonusPoints int) {
+func streakNotification(user db.User, streak int, bonusPoints int) (notification, bool) {
 	if streak < 4 && bonusPoints == 0 {
-		return
+		return notification{}, false
 	}
 
 	message := fmt.Sprintf("🎉 Ты попал в яблочко %d раз подряд и заработал %d бонусных очков! Так держать, твое футбольное чутье на высоте!", streak, bonusPoints)
@@ -144,12 +182,11 @@ func (s *Syncer) notifyUser(ctx context.Context, user db.User, streak int, bonus
 		message = fmt.Sprintf("🎉 Awesome job! You've nailed %d predictions in a row and scored %d bonus points! Your football instincts are on fire!", streak, bonusPoints)
 	}
 
-	err := s.notifier.SendTextNotification(contract.SendNotificationParams{
-		ChatID:  user.ChatID,
-		Message: telegram.EscapeMarkdown(message),
-	})
-
-	if err != nil {
-		log.Printf("Не получилось отправить уведомление пользователю %s: %v", user.ID, err)
-	}
+	return notification{
+		User: user,
+		Kind: db.NotificationStreaks,
+		Params: contract.SendNotificationParams{
+			Message: telegram.EscapeMarkdown(message),
+		},
+	}, true
 }
@@ -0,0 +1,151 @@
+package syncer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/user/project/internal/contract"
+	"github.com/user/project/internal/db"
+)
+
+const (
+	notificationKickoffReminder = "kickoff_reminder"
+
+	// Matches kicking off within this long after the first due one go into the same message
+	reminderGroupWindow = 30 * time.Minute
+
+	// A user "usually predicts" a competition or team based on this much history
+	reminderHistory                   = 60 * 24 * time.Hour
+	reminderMinCompetitionPredictions = 3
+	reminderMinTeamPredictions        = 2
+)
+
+// SendKickoffReminders reminds users about matches starting within the reminder lead time
+// that they haven't predicted although they usually predict the competition or the teams.
+// Each match is reminded about once; matches starting close together share one message.
+func (s *Syncer) SendKickoffReminders(ctx context.Context) (err error) {
+	run := s.startRun(ctx, JobReminders)
+	defer func() { s.finishRun(ctx, run, err) }()
+
+	now := s.now()
+	candidates, err := s.storage.GetKickoffReminderCandidates(ctx, now, now.Add(s.cfg.ReminderLead+reminderGroupWindow), db.ReminderHabits{
+		Since:          now.Add(-reminderHistory),
+		MinCompetition: reminderMinCompetitionPredictions,
+		MinTeam:        reminderMinTeamPredictions,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get reminder candidates: %w", err)
+	}
+
+	// Candidates come ordered by user and kickoff
+	for start := 0; start < len(candidates); {
+		end := start
+		for end < len(candidates) && candidates[end].User.ID == candidates[start].User.ID {
+			end++
+		}
+
+		user := candidates[start].User
+		matches := make([]db.Match, 0, end-start)
+		for _, c := range candidates[start:end] {
+			matches = append(matches, c.Match)
+		}
+		start = end
+
+		if err := s.remindUser(ctx, user, matches, now); err != nil {
+			log.Printf("Failed to send kickoff reminder to user %s: %v", user.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// remindUser sends one reminder for the user's matches kicking off together,
+// once the earliest of them not reminded about yet is within the lead time
+func (s *Syncer) remindUser(ctx context.Context, user db.User, matches []db.Match, now time.Time) error {
+	pending := make([]db.Match, 0, len(matches))
+	for _, match := range matches {
+		sent, err := s.storage.HasNotificationBeenSent(ctx, user.ID, notificationKickoffReminder, match.ID)
+		if err != nil {
+			return err
+		}
+		if !sent {
+			pending = append(pending, match)
+		}
+	}
+
+	if len(pending) == 0 || pending[0].MatchDate.After(now.Add(s.cfg.ReminderLead)) {
+		return nil
+	}
+
+	group := pending[:1]
+	for _, match := range pending[1:] {
+		if match.MatchDate.Sub(pending[0].MatchDate) > reminderGroupWindow {
+			break
+		}
+		group = append(group, match)
+	}
+
+	settings, err := s.storage.GetNotificationSettings(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	params := contract.SendNotificationParams{
+		Message:    bot.EscapeMarkdown(generateKickoffReminderText(user, group, settings.Location(s.cfg.Location))),
+		WebAppURL:  fmt.Sprintf("%s/matches", s.cfg.WebAppURL),
+		ButtonText: "Сделать прогноз",
+	}
+	if len(group) == 1 {
+		params.WebAppURL = fmt.Sprintf("%s/matches/%s", s.cfg.WebAppURL, group[0].ID)
+	}
+	if user.LanguageCode != nil && *user.LanguageCode != "ru" {
+		params.ButtonText = "Make your prediction"
+	}
+
+	sent, err := s.deliverBefore(ctx, user, db.NotificationReminders, params, group[0].MatchDate)
+	if err != nil {
+		return err
+	}
+
+	// Skipped reminders, e.g. when quiet hours last past kickoff, are recorded too
+	// so they aren't reconsidered on every run until the match starts
+	for _, match := range group {
+		if err := s.storage.LogNotification(ctx, user.ID, notificationKickoffReminder, match.ID); err != nil {
+			return err
+		}
+	}
+
+	if sent {
+		log.Printf("Sent kickoff reminder to user %s about %d matches", user.ID, len(group))
+	}
+	return nil
+}
+
+func generateKickoffReminderText(user db.User, matches []db.Match, loc *time.Location) string {
+	en := user.LanguageCode != nil && *user.LanguageCode != "ru"
+
+	if len(matches) == 1 {
+		m := matches[0]
+		kickoff := m.MatchDate.In(loc).Format("15:04")
+		if en {
+			return fmt.Sprintf("⏰ %s – %s kicks off at %s and you haven't made your prediction yet!", m.HomeTeam.ShortName, m.AwayTeam.ShortName, kickoff)
+		}
+		return fmt.Sprintf("⏰ %s – %s начнётся в %s, а твоего прогноза ещё нет!", m.HomeTeam.ShortName, m.AwayTeam.ShortName, kickoff)
+	}
+
+	var text strings.Builder
+	if en {
+		text.WriteString("⏰ These matches kick off soon and you haven't predicted them yet:\n")
+	} else {
+		text.WriteString("⏰ Скоро начнутся матчи без твоего прогноза:\n")
+	}
+	for _, m := range matches {
+		fmt.Fprintf(&text, "\n%s %s – %s", m.MatchDate.In(loc).Format("15:04"), m.HomeTeam.ShortName, m.AwayTeam.ShortName)
+	}
+
+	return text.String()
+}
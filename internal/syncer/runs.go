@@ -0,0 +1,74 @@
+package syncer
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/user/project/internal/db"
+	"github.com/user/project/internal/nanoid"
+)
+
+// Job names recorded in job_runs
+const (
+	JobTeamSync            = "team_sync"
+	JobMatchSync           = "match_sync"
+	JobLiveMatchSync       = "live_match_sync"
+	JobMatchReconcile      = "match_reconcile"
+	JobStandingsSync       = "standings_sync"
+	JobSettlement          = "settlement"
+	JobSeasons             = "seasons"
+	JobNotifications       = "notifications"
+	JobPopularity          = "popularity"
+	JobReminders           = "kickoff_reminders"
+	JobQueuedNotifications = "queued_notifications"
+)
+
+// jobRunRetention is how long job history is kept
+const jobRunRetention = 14 * 24 * time.Hour
+
+// startRun records that a job began. Failing to record it never stops the job itself.
+func (s *Syncer) startRun(ctx context.Context, job string) *db.JobRun {
+	run := &db.JobRun{
+		ID:        nanoid.Must(),
+		Job:       job,
+		Status:    db.JobRunStatusRunning,
+		StartedAt: s.now(),
+	}
+
+	if err := s.storage.StartJobRun(ctx, *run); err != nil {
+		log.Printf("Failed to record start of job %s: %v", job, err)
+	}
+
+	return run
+}
+
+// finishRun records the outcome of a run, even when ctx was cancelled by shutdown
+func (s *Syncer) finishRun(ctx context.Context, run *db.JobRun, err error) {
+	finishedAt := s.now()
+	run.FinishedAt = &finishedAt
+	run.Status = db.JobRunStatusSucceeded
+	if err != nil {
+		msg := err.Error()
+		run.Status = db.JobRunStatusFailed
+		run.Error = &msg
+	}
+
+	if err := s.storage.FinishJobRun(context.WithoutCancel(ctx), *run); err != nil {
+		log.Printf("Failed to record end of job %s: %v", run.Job, err)
+	}
+}
+
+// PruneJobRuns drops job history past the retention period
+func (s *Syncer) PruneJobRuns(ctx context.Context) error {
+	deleted, err := s.storage.DeleteJobRunsBefore(ctx, s.now().Add(-jobRunRetention))
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		log.Printf("Pruned %d job runs", deleted)
+	}
+
+	return nil
+}
@@ -4,15 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/user/project/internal/clock"
 	"github.com/user/project/internal/db"
 	"github.com/user/project/internal/nanoid"
 	"log"
-	"time"
 )
 
-func (s *Syncer) ManageSeasons(ctx context.Context) error {
-	now := time.Now()
-	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+func (s *Syncer) ManageSeasons(ctx context.Context) (err error) {
+	run := s.startRun(ctx, JobSeasons)
+	defer func() { s.finishRun(ctx, run, err) }()
+
+	firstOfMonth := clock.StartOfMonth(s.now(), s.cfg.Location)
 	lastOfMonth := firstOfMonth.AddDate(0, 1, -1)
 
 	activeSeason, err := s.storage.GetActiveSeason(ctx, db.SeasonTypeMonthly)
@@ -24,7 +26,10 @@ func (s *Syncer) ManageSeasons(ctx context.Context) error {
 	if errors.Is(err, db.ErrNotFound) {
 		newSeasonRequired = true
 	} else {
-		if !(activeSeason.StartDate.Equal(firstOfMonth) && activeSeason.EndDate.Equal(lastOfMonth)) {
+		// Compare calendar months in the league timezone rather than exact timestamps,
+		// so a season stored with another offset doesn't roll over in the middle of a month
+		start := activeSeason.StartDate.In(s.cfg.Location)
+		if start.Year() != firstOfMonth.Year() || start.Month() != firstOfMonth.Month() {
 			newSeasonRequired = true
 		}
 	}
@@ -35,6 +40,10 @@ func (s *Syncer) ManageSeasons(ctx context.Context) error {
 			if err != nil {
 				return fmt.Errorf("failed to mark previous season inactive: %w", err)
 			}
+
+			if err := s.AwardSeasonBadges(ctx, activeSeason); err != nil {
+				log.Printf("Failed to award badges for season %s: %v", activeSeason.ID, err)
+			}
 		}
 
 		seasonCount, err := s.storage.CountSeasons(ctx, db.SeasonTypeMonthly)
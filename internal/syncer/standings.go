@@ -0,0 +1,73 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/user/project/internal/db"
+	"log"
+)
+
+// SyncStandings refreshes league tables of every enabled competition
+func (s *Syncer) SyncStandings(ctx context.Context) (err error) {
+	run := s.startRun(ctx, JobStandingsSync)
+	defer func() { s.finishRun(ctx, run, err) }()
+
+	competitions, err := s.storage.ListEnabledCompetitions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list competitions: %w", err)
+	}
+
+	var failed []error
+	for _, c := range competitions {
+		if err := s.syncCompetitionStandings(ctx, c.Code); err != nil {
+			log.Printf("Failed to sync standings for competition %s: %v", c.Code, err)
+			failed = append(failed, fmt.Errorf("%s: %w", c.Code, err))
+		}
+	}
+
+	return errors.Join(failed...)
+}
+
+func (s *Syncer) syncCompetitionStandings(ctx context.Context, competition string) error {
+	rows, err := s.cfg.Provider.GetStandings(ctx, competition)
+	if err != nil {
+		return fmt.Errorf("failed to fetch standings: %w", err)
+	}
+
+	// Cups have no table until the group stage is drawn; keep whatever was stored
+	if len(rows) == 0 {
+		return nil
+	}
+
+	standings := make([]db.Standing, 0, len(rows))
+	for _, row := range rows {
+		team, err := s.resolveTeam(ctx, competition, "", row.Team)
+		if err != nil {
+			log.Printf("Failed to resolve team in %s standings: %v", competition, err)
+			continue
+		}
+
+		standing := db.Standing{
+			Group:          row.Group,
+			TeamID:         team.ID,
+			Position:       row.Position,
+			Played:         row.PlayedGames,
+			Won:            row.Won,
+			Draw:           row.Draw,
+			Lost:           row.Lost,
+			GoalsFor:       row.GoalsFor,
+			GoalsAgainst:   row.GoalsAgainst,
+			GoalDifference: row.GoalDifference,
+			Points:         row.Points,
+		}
+		if row.Form != "" {
+			form := row.Form
+			standing.Form = &form
+		}
+
+		standings = append(standings, standing)
+	}
+
+	return s.storage.ReplaceStandings(ctx, competition, standings)
+}
@@ -2,12 +2,13 @@ package syncer
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/user/project/internal/clock"
 	"github.com/user/project/internal/contract"
+	"github.com/user/project/internal/football"
 	"log"
-	"math"
-	"net/http"
+	"sync"
 	"time"
 
 	"github.com/user/project/internal/db"
@@ -17,10 +18,15 @@ type storager interface {
 	SaveMatch(ctx context.Context, match db.Match) error
 	GetTeamByName(ctx context.Context, name string) (db.Team, error)
 	GetTeamByID(ctx context.Context, id string) (db.Team, error)
+	HasTeamAlias(ctx context.Context, teamID, alias string) (bool, error)
+	AddTeamAlias(ctx context.Context, teamID, alias string) error
+	RecordUnresolvedTeam(ctx context.Context, team db.UnresolvedTeam) error
+	ResolveTeam(ctx context.Context, name string) error
 	GetCompletedMatchesWithoutCompletedPredictions(ctx context.Context) ([]db.Match, error)
 	GetPredictionsForMatch(ctx context.Context, matchID string) ([]db.Prediction, error)
 	UpdatePredictionResult(ctx context.Context, matchID, userID string, points int) error
 	GetActiveSeasons(ctx context.Context) ([]db.Season, error)
+	GetSeasonsForDate(ctx context.Context, t time.Time) ([]db.Season, error)
 	GetActiveSeason(ctx context.Context, seasonType string) (db.Season, error)
 	UpdateUserLeaderboardPoints(ctx context.Context, userID, seasonID string, points int) error
 	UpdateUserPoints(ctx context.Context, userID string, isCorrect bool) error
@@ -30,20 +36,42 @@ type storager interface {
 	MarkSeasonInactive(ctx context.Context, seasonID string) error
 	CreateSeason(ctx context.Context, season db.Season) error
 	CountSeasons(ctx context.Context, seasonType string) (int, error)
-	GetMatchesForTeam(ctx context.Context, teamID string, hoursAhead int) ([]db.Match, error)
+	GetMatchesForTeam(ctx context.Context, teamID string, from, to time.Time) ([]db.Match, error)
 	GetAllUsers(ctx context.Context) ([]db.User, error)
 	GetWeeklyRecap(ctx context.Context, userID string) (db.WeeklyRecap, error)
 	HasNotificationBeenSent(ctx context.Context, userID, notificationType, relatedID string) (bool, error)
 	LogNotification(ctx context.Context, userID, notificationType, relatedID string) error
+	GetNotificationSettings(ctx context.Context, userID string) (db.NotificationSettings, error)
+	QueueNotification(ctx context.Context, n db.QueuedNotification) error
+	GetDueNotifications(ctx context.Context, now time.Time) ([]db.QueuedNotification, error)
+	DeleteQueuedNotification(ctx context.Context, id string) error
+	GetKickoffReminderCandidates(ctx context.Context, from, to time.Time, habits db.ReminderHabits) ([]db.ReminderCandidate, error)
 	GetAllUsersWithFavoriteTeam(ctx context.Context) ([]db.User, error)
-	GetActiveMatches(ctx context.Context, userID string) ([]db.Match, error)
+	GetActiveMatches(ctx context.Context, userID string, now time.Time, opts ...db.MatchFilter) ([]db.Match, error)
 	CreateUser(user db.User) error
-	GetLastMatchesByTeamID(ctx context.Context, teamID string, limit int) ([]db.Match, error)
+	GetLastMatchesByTeamID(ctx context.Context, teamID string, before time.Time, limit int) ([]db.Match, error)
 	SavePrediction(ctx context.Context, prediction db.Prediction) error
-	GetTodayMostPopularMatch(ctx context.Context) (db.Match, error)
+	GetTodayMostPopularMatch(ctx context.Context, from, to time.Time) (db.Match, error)
 	GetMatchByID(ctx context.Context, matchID string) (db.Match, error)
 	GetPredictionsByUserID(ctx context.Context, uid string, opts ...db.PredictionFilter) ([]db.Prediction, error)
 	GetUserMonthlyRank(ctx context.Context, userID string) (int, int, error)
+	SnapshotLeaderboard(ctx context.Context, seasonID string, day time.Time) error
+	GetLeaderboard(ctx context.Context, seasonID string) ([]db.LeaderboardEntry, error)
+	AwardBadge(ctx context.Context, userID, badgeID string) (bool, error)
+	GetBadgeByID(ctx context.Context, id string) (db.Badge, error)
+	CountMatchdayPredictions(ctx context.Context, userID, competitionCode, season string, matchday int) (int, int, error)
+	ListEnabledCompetitions(ctx context.Context) ([]db.Competition, error)
+	UpdateCompetitionSync(ctx context.Context, code string, cursor *time.Time, syncedAt time.Time, full bool) error
+	CountLiveMatches(ctx context.Context, now time.Time, lead, overrun time.Duration) (int, error)
+	GetNextKickoff(ctx context.Context, now time.Time) (time.Time, error)
+	ReplaceStandings(ctx context.Context, competitionCode string, standings []db.Standing) error
+	GetLeaguePositions(ctx context.Context, competitionCode string) (map[string]int, error)
+	GetMatchPopularityInputs(ctx context.Context, from, to time.Time) ([]db.MatchPopularityInputs, error)
+	UpdateMatchPopularity(ctx context.Context, scores map[string]float64) error
+	RecordCompetitionSyncError(ctx context.Context, code, syncErr string, at time.Time) error
+	StartJobRun(ctx context.Context, run db.JobRun) error
+	FinishJobRun(ctx context.Context, run db.JobRun) error
+	DeleteJobRunsBefore(ctx context.Context, before time.Time) (int64, error)
 }
 type Config struct {
 	APIBaseURL      string
@@ -53,93 +81,58 @@ type Config struct {
 	ImagePreviewURL string
 	ChannelChatID   int64
 	BotWebApp       string
+	Location        *time.Location // League timezone for season boundaries and "today"
+	Clock           clock.Clock
+	Provider        FootballProvider // Defaults to football-data.org with APIBaseURL and APIKey
+	SyncWindowPast  time.Duration    // How far back incremental match sync looks, 2 days by default
+	SyncWindowAhead time.Duration    // How far ahead incremental match sync looks, 7 days by default
+	ReminderLead    time.Duration    // How long before kickoff users are reminded of unpredicted matches, 1 hour by default
 }
 type Syncer struct {
 	storage  storager
 	notifier noifier
 	cfg      Config
-}
-type Odds struct {
-	HomeWin *float64 `json:"homeWin"`
-	Draw    *float64 `json:"draw"`
-	AwayWin *float64 `json:"awayWin"`
-}
-type APIMatch struct {
-	Area struct {
-		Id   int    `json:"id"`
-		Name string `json:"name"`
-		Code string `json:"code"`
-		Flag string `json:"flag"`
-	} `json:"area"`
-	Competition struct {
-		Id     int    `json:"id"`
-		Name   string `json:"name"`
-		Code   string `json:"code"`
-		Type   string `json:"type"`
-		Emblem string `json:"emblem"`
-	} `json:"competition"`
-	Season struct {
-		Id              int     `json:"id"`
-		StartDate       string  `json:"startDate"`
-		EndDate         string  `json:"endDate"`
-		CurrentMatchday int     `json:"currentMatchday"`
-		Winner          *string `json:"winner"`
-	} `json:"season"`
-	Id          int       `json:"id"`
-	UtcDate     time.Time `json:"utcDate"`
-	Status      string    `json:"status"`
-	Matchday    int       `json:"matchday"`
-	Stage       string    `json:"stage"`
-	Group       *string   `json:"group"`
-	LastUpdated time.Time `json:"lastUpdated"`
-	HomeTeam    struct {
-		Id         *int    `json:"id"`
-		Name       *string `json:"name"`
-		ShortName  *string `json:"shortName"`
-		Tla        *string `json:"tla"`
-		Crest      *string `json:"crest"`
-		LeagueRank *int    `json:"leagueRank"`
-	} `json:"homeTeam"`
-	AwayTeam struct {
-		Id         *int    `json:"id"`
-		Name       *string `json:"name"`
-		ShortName  *string `json:"shortName"`
-		Tla        *string `json:"tla"`
-		Crest      *string `json:"crest"`
-		LeagueRank *int    `json:"leagueRank"`
-	} `json:"awayTeam"`
-	Score struct {
-		Winner   *string `json:"winner"`
-		Duration string  `json:"duration"`
-		FullTime struct {
-			Home *int `json:"home"`
-			Away *int `json:"away"`
-		} `json:"fullTime"`
-		HalfTime struct {
-			Home *int `json:"home"`
-			Away *int `json:"away"`
-		} `json:"halfTime"`
-	} `json:"score"`
-	Odds     *Odds `json:"odds"`
-	Referees []struct {
-		Id          int    `json:"id"`
-		Name        string `json:"name"`
-		Type        string `json:"type"`
-		Nationality string `json:"nationality"`
-	} `json:"referees"`
-}
 
+	settleMu sync.Mutex // Settlement runs from several jobs and must not award points twice
+}
 type noifier interface {
 	SendTextNotification(params contract.SendNotificationParams) error
 	SendPhotoNotification(params contract.SendNotificationParams) error
 }
 
-type APIResponse struct {
-	Matches []APIMatch `json:"matches"`
+// FootballProvider supplies normalised teams, matches, scores and odds
+type FootballProvider interface {
+	GetTeams(ctx context.Context, competition string) ([]football.Team, error)
+	GetMatches(ctx context.Context, competition string, filter football.MatchFilter) ([]football.Match, error)
+	GetStandings(ctx context.Context, competition string) ([]football.Standing, error)
 }
 
 // NewSyncer creates a new instance of the syncer
 func NewSyncer(storage storager, notifier noifier, cfg Config) *Syncer {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+
+	if cfg.Clock == nil {
+		cfg.Clock = clock.Real()
+	}
+
+	if cfg.SyncWindowPast == 0 {
+		cfg.SyncWindowPast = 2 * 24 * time.Hour
+	}
+
+	if cfg.SyncWindowAhead == 0 {
+		cfg.SyncWindowAhead = 7 * 24 * time.Hour
+	}
+
+	if cfg.ReminderLead == 0 {
+		cfg.ReminderLead = time.Hour
+	}
+
+	if cfg.Provider == nil {
+		cfg.Provider = football.NewFootballData(cfg.APIBaseURL, cfg.APIKey)
+	}
+
 	return &Syncer{
 		storage:  storage,
 		notifier: notifier,
@@ -147,269 +140,293 @@ func NewSyncer(storage storager, notifier noifier, cfg Config) *Syncer {
 	}
 }
 
+// now returns the current time in the league timezone
+func (s *Syncer) now() time.Time {
+	return s.cfg.Clock.Now().In(s.cfg.Location)
+}
+
 func statusMapper(status string) string {
 	// in db, we have only scheduled, ongoing, completed
 	switch status {
-	case "SCHEDULED", "TIMED":
+	case football.StatusScheduled:
 		return db.MatchStatusScheduled
-	case "IN_PLAY", "PAUSED":
+	case football.StatusLive:
 		return db.MatchStatusOngoing
-	case "FINISHED":
+	case football.StatusFinished:
 		return db.MatchStatusCompleted
 	default:
 		return "unknown"
 	}
 }
 
-func executeWithRateLimit(ctx context.Context, client *http.Client, req *http.Request, limit int, lastRequestTime *time.Time) (*http.Response, error) {
-	requestInterval := time.Minute / time.Duration(limit)
-	elapsed := time.Since(*lastRequestTime)
-	if elapsed < requestInterval {
-		time.Sleep(requestInterval - elapsed)
+func (s *Syncer) SyncTeams(ctx context.Context) (err error) {
+	run := s.startRun(ctx, JobTeamSync)
+	defer func() { s.finishRun(ctx, run, err) }()
+
+	competitions, err := s.storage.ListEnabledCompetitions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list competitions: %w", err)
 	}
-	*lastRequestTime = time.Now()
 
-	var retryCount int
-	for {
-		resp, err := client.Do(req)
+	var failed []error
+	for _, c := range competitions {
+		competition := c.Code
+
+		log.Printf("Starting team sync for competition: %s", competition)
+
+		teams, err := s.cfg.Provider.GetTeams(ctx, competition)
 		if err != nil {
-			return nil, fmt.Errorf("failed to execute request: %w", err)
+			log.Printf("Failed to fetch teams for competition %s: %v", competition, err)
+			failed = append(failed, fmt.Errorf("%s: %w", competition, err))
+			continue
 		}
 
-		if resp.StatusCode == http.StatusOK {
-			return resp, nil
+		for _, team := range teams {
+			err := s.storage.SaveTeam(ctx, db.Team{
+				ID:           team.ID,
+				Name:         team.Name,
+				ShortName:    team.ShortName,
+				Abbreviation: team.TLA,
+				CrestURL:     team.Crest,
+				Country:      team.AreaCode,
+			})
+			if err != nil {
+				log.Printf("Failed to save team %s (%s): %v", team.ID, team.Name, err)
+			}
 		}
+	}
 
-		if resp.StatusCode == http.StatusTooManyRequests {
-			retryCount++
-			if retryCount > 3 { // Limit retries
-				log.Printf("Too many retries. Skipping request.")
-				return nil, fmt.Errorf("too many retries for request")
-			}
+	return errors.Join(failed...)
+}
 
-			resetTime := time.Now().Add(10 * time.Second) // Default retry delay
-			if val := resp.Header.Get("X-RequestCounter-Reset"); val != "" {
-				// value is a number of seconds to wait
-				if waitDuration, err := time.ParseDuration(val + "s"); err == nil {
-					resetTime = time.Now().Add(waitDuration)
-				}
-			}
+// syncMode tells how a match sync treats the competition's sync cursor
+type syncMode int
+
+const (
+	// syncIncremental saves the matches changed since the cursor and moves it forward
+	syncIncremental syncMode = iota
+	// syncLive skips matches like syncIncremental but leaves the cursor alone: its window is
+	// narrower than the incremental one, which would miss changes the cursor already passed
+	syncLive
+	// syncFull saves every match whatever the cursor says
+	syncFull
+)
 
-			waitDuration := time.Until(resetTime)
-			log.Printf("Rate limit reached. Retrying after %v...", waitDuration)
-			time.Sleep(waitDuration)
-			continue
-		}
+// SyncMatches fetches matches kicking off within the sync window and stores the ones
+// the provider changed since the last run
+func (s *Syncer) SyncMatches(ctx context.Context) error {
+	now := s.now()
+	return s.syncMatches(ctx, football.MatchFilter{
+		DateFrom: now.Add(-s.cfg.SyncWindowPast),
+		DateTo:   now.Add(s.cfg.SyncWindowAhead),
+	}, JobMatchSync, syncIncremental)
+}
 
-		return nil, fmt.Errorf("unexpected response status: %d", resp.StatusCode)
-	}
+// SyncLiveMatches is SyncMatches narrowed down to the matches around today, cheap enough
+// to run every minute while games are in play
+func (s *Syncer) SyncLiveMatches(ctx context.Context) error {
+	now := s.now()
+	return s.syncMatches(ctx, football.MatchFilter{
+		DateFrom: now.Add(-24 * time.Hour),
+		DateTo:   now.Add(24 * time.Hour),
+	}, JobLiveMatchSync, syncLive)
 }
 
-func (s *Syncer) fetchAPIData(ctx context.Context, endpoint string, lastRequestTime *time.Time, result interface{}) error {
-	client := &http.Client{Timeout: 10 * time.Second}
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s%s", s.cfg.APIBaseURL, endpoint), nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("X-Auth-Token", s.cfg.APIKey)
+// ReconcileMatches fetches whole seasons and stores every match, catching anything
+// the incremental sync missed
+func (s *Syncer) ReconcileMatches(ctx context.Context) error {
+	return s.syncMatches(ctx, football.MatchFilter{}, JobMatchReconcile, syncFull)
+}
+
+// syncMatches stores matches of every enabled competition and settles predictions
+// right away if any of them finished. Competitions that fail don't hold back the others.
+func (s *Syncer) syncMatches(ctx context.Context, filter football.MatchFilter, job string, mode syncMode) (err error) {
+	run := s.startRun(ctx, job)
+	defer func() { s.finishRun(ctx, run, err) }()
 
-	resp, err := executeWithRateLimit(ctx, client, req, 10, lastRequestTime)
+	competitions, err := s.storage.ListEnabledCompetitions(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to fetch data: %w", err)
+		return fmt.Errorf("failed to list competitions: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected response status: %d", resp.StatusCode)
+	var finished int
+	var failed []error
+	for _, c := range competitions {
+		saved, n, err := s.syncCompetitionMatches(ctx, c, filter, mode)
+		run.MatchesUpserted += saved
+		if err != nil {
+			log.Printf("Failed to sync matches for competition %s: %v", c.Code, err)
+			failed = append(failed, fmt.Errorf("%s: %w", c.Code, err))
+			if err := s.storage.RecordCompetitionSyncError(ctx, c.Code, err.Error(), s.now()); err != nil {
+				log.Printf("Failed to record sync error for competition %s: %v", c.Code, err)
+			}
+		}
+
+		// Finished matches move the table
+		if n > 0 && mode != syncFull {
+			if err := s.syncCompetitionStandings(ctx, c.Code); err != nil {
+				log.Printf("Failed to sync standings for competition %s: %v", c.Code, err)
+			}
+		}
+		finished += n
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-		return fmt.Errorf("failed to decode API response: %w", err)
+	if finished > 0 {
+		log.Printf("%d matches finished, settling predictions", finished)
+		if err := s.ProcessPredictions(ctx); err != nil {
+			failed = append(failed, fmt.Errorf("failed to process predictions: %w", err))
+		}
 	}
 
-	return nil
+	return errors.Join(failed...)
 }
 
-func (s *Syncer) SyncTeams(ctx context.Context) error {
-	competitions := []string{"PL", "PD", "FL1", "SA", "BL1", "CL"} // England, Spain, France, Italy, Germany, Champions League
-	lastRequestTime := time.Now().Add(-time.Minute)
+// syncCompetitionMatches returns how many matches were saved and how many of them changed to completed
+func (s *Syncer) syncCompetitionMatches(ctx context.Context, c db.Competition, filter football.MatchFilter, mode syncMode) (int, int, error) {
+	competition := c.Code
+	full := mode == syncFull
+	now := s.now()
 
-	for _, competition := range competitions {
-		log.Printf("Starting team sync for competition: %s", competition)
+	matches, err := s.cfg.Provider.GetMatches(ctx, competition, filter)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch matches: %w", err)
+	}
 
-		var apiResp struct {
-			Teams []struct {
-				ID        int    `json:"id"`
-				Name      string `json:"name"`
-				ShortName string `json:"shortName"`
-				Tla       string `json:"tla"`
-				Crest     string `json:"crest"`
-				Area      struct {
-					ID   int    `json:"id"`
-					Name string `json:"name"`
-					Code string `json:"code"`
-				}
-			} `json:"teams"`
+	// Real table positions beat the provider's leagueRank for popularity
+	positions, err := s.storage.GetLeaguePositions(ctx, competition)
+	if err != nil {
+		log.Printf("Failed to get league positions for competition %s: %v", competition, err)
+	}
+
+	// The cursor stays below the first change that failed to be stored so the next run retries it
+	var cursor, failedAt *time.Time
+	fail := func(match football.Match) {
+		if !match.LastUpdated.IsZero() && (failedAt == nil || match.LastUpdated.Before(*failedAt)) {
+			updated := match.LastUpdated
+			failedAt = &updated
 		}
+	}
 
-		if err := s.fetchAPIData(ctx, fmt.Sprintf("/competitions/%s/teams", competition), &lastRequestTime, &apiResp); err != nil {
-			log.Printf("Failed to fetch teams for competition %s: %v", competition, err)
+	var saved, skipped, finished int
+	for _, match := range matches {
+		if match.HomeTeam.Name == "" || match.AwayTeam.Name == "" {
 			continue
 		}
 
-		for _, team := range apiResp.Teams {
-			err := s.storage.SaveTeam(ctx, db.Team{
-				ID:           fmt.Sprintf("%d", team.ID),
-				Name:         team.Name,
-				ShortName:    team.ShortName,
-				Abbreviation: team.Tla,
-				CrestURL:     team.Crest,
-				Country:      team.Area.Code,
-			})
-			if err != nil {
-				log.Printf("Failed to save team %d (%s): %v", team.ID, team.Name, err)
-			}
+		// Providers without lastUpdated get every match saved
+		if !full && c.SyncCursor != nil && !match.LastUpdated.IsZero() && !match.LastUpdated.After(*c.SyncCursor) {
+			skipped++
+			continue
 		}
-	}
 
-	return nil
-}
-
-func (s *Syncer) SyncMatches(ctx context.Context) error {
-	competitions := []string{"PL", "PD", "FL1", "SA", "BL1", "CL"} // Competition codes
-	lastRequestTime := time.Now().Add(-time.Minute)
+		homeTeam, err := s.resolveTeam(ctx, competition, match.ID, match.HomeTeam)
+		if err != nil {
+			log.Printf("Failed to retrieve home team in competition %s: %v", competition, err)
+			fail(match)
+			continue
+		}
 
-	for _, competition := range competitions {
-		var apiResp APIResponse
-		if err := s.fetchAPIData(ctx, fmt.Sprintf("/competitions/%s/matches", competition), &lastRequestTime, &apiResp); err != nil {
-			log.Printf("Failed to fetch matches for competition %s: %v", competition, err)
+		awayTeam, err := s.resolveTeam(ctx, competition, match.ID, match.AwayTeam)
+		if err != nil {
+			log.Printf("Failed to retrieve away team in competition %s: %v", competition, err)
+			fail(match)
 			continue
 		}
 
-		for _, match := range apiResp.Matches {
-			if match.HomeTeam.Name == nil || match.AwayTeam.Name == nil {
-				continue
-			}
+		previousStatus := ""
+		if existing, err := s.storage.GetMatchByID(ctx, match.ID); err == nil {
+			previousStatus = existing.Status
+		} else if !errors.Is(err, db.ErrNotFound) {
+			log.Printf("Failed to get match %s: %v", match.ID, err)
+		}
 
-			homeTeam, err := s.storage.GetTeamByName(ctx, *match.HomeTeam.Name)
-			if err != nil {
-				log.Printf("Failed to retrieve home team ID in competition %s: %v", competition, err)
-				continue
-			}
+		if pos, ok := positions[homeTeam.ID]; ok {
+			match.HomeTeam.LeagueRank = &pos
+		}
+		if pos, ok := positions[awayTeam.ID]; ok {
+			match.AwayTeam.LeagueRank = &pos
+		}
 
-			awayTeam, err := s.storage.GetTeamByName(ctx, *match.AwayTeam.Name)
-			if err != nil {
-				log.Printf("Failed to retrieve away team ID in competition %s: %v", competition, err)
-				continue
-			}
+		status := statusMapper(match.Status)
+		popularityScore := ComputePopularityScore(match)
+		err = s.storage.SaveMatch(ctx, toDBMatch(match, homeTeam.ID, awayTeam.ID, status, popularityScore))
 
-			matchDate := match.UtcDate // Assume valid date parsing here
-			popularityScore := ComputePopularityScore(match)
-			err = s.storage.SaveMatch(ctx, db.Match{
-				ID:         fmt.Sprintf("%d", match.Id),
-				Tournament: match.Competition.Name,
-				HomeTeamID: homeTeam.ID,
-				AwayTeamID: awayTeam.ID,
-				MatchDate:  matchDate,
-				Status:     statusMapper(match.Status),
-				HomeScore:  match.Score.FullTime.Home,
-				AwayScore:  match.Score.FullTime.Away,
-				HomeOdds:   match.Odds.HomeWin,
-				DrawOdds:   match.Odds.Draw,
-				AwayOdds:   match.Odds.AwayWin,
-				Popularity: popularityScore,
-			})
+		if err != nil {
+			log.Printf("Failed to save match %s in competition %s: %v", match.ID, competition, err)
+			fail(match)
+			continue
+		}
 
-			if err != nil {
-				log.Printf("Failed to save match %d in competition %s: %v", match.Id, competition, err)
+		if previousStatus != "" && previousStatus != status {
+			log.Printf("Match %s in %s: %s -> %s", match.ID, competition, previousStatus, status)
+			if status == db.MatchStatusCompleted {
+				finished++
 			}
 		}
 
+		saved++
+		if !match.LastUpdated.IsZero() && (cursor == nil || match.LastUpdated.After(*cursor)) {
+			updated := match.LastUpdated
+			cursor = &updated
+		}
 	}
 
-	return nil
-}
-
-var popularTeams = map[string]bool{
-	"FCB": true,
-	"ATL": true,
-	"ATH": true,
-	"RMA": true,
-	"INT": true,
-	"NAP": true,
-	"ATA": true,
-	"JUV": true,
-	"LAZ": true,
-	"ROM": true,
-	"MIL": true,
-	"B04": true,
-	"BVB": true,
-	"PSG": true,
-	"MCI": true,
-	"MUN": true,
-	"TOT": true,
-	"CHE": true,
-	"LIV": true,
-	"ARS": true,
-}
-
-func ComputePopularityScore(match APIMatch) float64 {
-	// 1. Team Ranking Score (lower rank is better, so we invert)
-	rankScore := 100 - getLeagueRankScore(match.HomeTeam.LeagueRank) - getLeagueRankScore(match.AwayTeam.LeagueRank)
-
-	// 2. Odds Competitiveness Score (closer odds = more competitive = higher score)
-	oddsScore := 50 - getOddsSpread(match.Odds)
-
-	// 3. Match Timing Bonus (later matches get extra points)
-	timeBonus := getTimeBonus(match.UtcDate)
-
-	// 4. Popularity Bonus (if either team is in the popular list)
-	popularityBonus := getPopularityBonus(match.HomeTeam.Name, match.AwayTeam.Name)
-
-	// Calculate final score
-	finalScore := float64(rankScore) + float64(oddsScore) + float64(timeBonus) + float64(popularityBonus)
-	return finalScore
-}
-
-// getPopularityBonus checks if a team is in the popular list and assigns extra points
-func getPopularityBonus(homeTeam, awayTeam *string) int {
-	bonus := 0
-	if homeTeam != nil && popularTeams[*homeTeam] {
-		bonus += 100
+	if full {
+		log.Printf("Reconciled %s: %d matches saved", competition, saved)
+	} else if saved > 0 {
+		log.Printf("Synced %s: %d matches saved, %d unchanged", competition, saved, skipped)
 	}
 
-	if awayTeam != nil && popularTeams[*awayTeam] {
-		bonus += 100
+	if failedAt != nil && cursor != nil && !cursor.Before(*failedAt) {
+		below := failedAt.Add(-time.Nanosecond)
+		cursor = &below
+	}
+	if mode == syncLive {
+		cursor = nil
 	}
 
-	return bonus
+	return saved, finished, s.storage.UpdateCompetitionSync(ctx, competition, cursor, now, full)
 }
 
-// getLeagueRankScore handles cases where leagueRank is nil
-func getLeagueRankScore(rank *int) int {
-	if rank == nil {
-		return 50 // Assign a mid-value if no rank is available
+func toDBMatch(match football.Match, homeTeamID, awayTeamID, status string, popularity float64) db.Match {
+	res := db.Match{
+		ID:                match.ID,
+		Tournament:        match.Competition.Name,
+		HomeTeamID:        homeTeamID,
+		AwayTeamID:        awayTeamID,
+		MatchDate:         match.UTCDate,
+		Status:            status,
+		HomeScore:         match.Score.Home,
+		AwayScore:         match.Score.Away,
+		HomeOdds:          match.Odds.HomeWin,
+		DrawOdds:          match.Odds.Draw,
+		AwayOdds:          match.Odds.AwayWin,
+		Popularity:        popularity,
+		CompetitionCode:   match.Competition.Code,
+		CompetitionSeason: match.Season,
+		CompetitionEmblem: match.Competition.Emblem,
+		AreaCode:          match.Area.Code,
+		AreaName:          match.Area.Name,
+		Stage:             match.Stage,
 	}
-	return *rank
-}
 
-// getOddsSpread calculates the odds spread or assigns a high default if odds are missing
-func getOddsSpread(odds *Odds) float64 {
-	if odds == nil || odds.HomeWin == nil || odds.AwayWin == nil {
-		return 50.0 // High value to deprioritize matches without odds
+	if match.Matchday > 0 {
+		matchday := match.Matchday
+		res.Matchday = &matchday
 	}
-	return math.Abs(*odds.HomeWin - *odds.AwayWin)
-}
 
-// getTimeBonus gives extra points for prime-time matches
-func getTimeBonus(utcDate time.Time) int {
-	hour := utcDate.Hour()
+	if match.Group != "" {
+		group := match.Group
+		res.Group = &group
+	}
 
-	// Assign bonuses based on match timing
-	if hour >= 19 {
-		return 20 // Prime-time evening matches
-	} else if hour >= 16 {
-		return 10 // Late afternoon matches
+	for _, referee := range match.Referees {
+		res.Referees = append(res.Referees, db.Referee{
+			Name:        referee.Name,
+			Type:        referee.Type,
+			Nationality: referee.Nationality,
+		})
 	}
-	return 0 // Earlier matches get no bonus
+
+	return res
 }
@@ -2,35 +2,61 @@ package syncer_test
 
 import (
 	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/user/project/internal/clock"
 	"github.com/user/project/internal/contract"
 	"github.com/user/project/internal/db"
+	"github.com/user/project/internal/football"
 	"github.com/user/project/internal/syncer"
-	"os"
-	"testing"
-	"time"
 )
 
-func setupTestDB(t *testing.T) (*db.Storage, func()) {
-	// Create a temporary file for SQLite
-	tempFile, err := os.CreateTemp("", "test.db")
-	assert.NoError(t, err)
+// searchMigrations build user search, which needs the sqlite_fts5 build tag; nothing here depends on it
+var searchMigrations = map[string]bool{
+	"6_user_search.sql":      true,
+	"20_user_search_key.sql": true,
+}
 
-	// Ensure the file is removed after the test
-	cleanup := func() {
-		os.Remove(tempFile.Name())
-	}
+func setupTestDB(t *testing.T) *db.Storage {
+	conn, err := sql.Open("sql", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	// Migrations are named <n>_<name>.sql and must run in numeric order
+	files, err := filepath.Glob("../../migrations/*.sql")
+	require.NoError(t, err)
+	sort.Slice(files, func(i, j int) bool {
+		return migrationNumber(files[i]) < migrationNumber(files[j])
+	})
+
+	for _, file := range files {
+		if searchMigrations[filepath.Base(file)] {
+			continue
+		}
 
-	// Initialize the storage with the temp DB
-	storage, err := db.ConnectDB(tempFile.Name())
-	assert.NoError(t, err)
+		query, err := os.ReadFile(file)
+		require.NoError(t, err)
 
-	// Run migrations
-	//err = db.RunMigrations(storage.DB())
-	//assert.NoError(t, err)
+		_, err = conn.Exec(string(query))
+		require.NoError(t, err, file)
+	}
 
-	return storage, cleanup
+	return db.NewStorage(conn)
+}
+
+func migrationNumber(path string) int {
+	n, _ := strconv.Atoi(strings.SplitN(filepath.Base(path), "_", 2)[0])
+	return n
 }
 
 type MockNotifier struct {
@@ -42,28 +68,44 @@ func (m *MockNotifier) SendTextNotification(params contract.SendNotificationPara
 	return args.Error(0)
 }
 
+func (m *MockNotifier) SendPhotoNotification(params contract.SendNotificationParams) error {
+	args := m.Called(params)
+	return args.Error(0)
+}
+
+// newReplaySyncer serves football-data.org responses recorded in testdata/fixtures/<phase>
+func newReplaySyncer(storage *db.Storage, notifier *MockNotifier, phase string, now time.Time) *syncer.Syncer {
+	provider := football.NewFootballData("https://api.football-data.org/v4", "test_api_key",
+		football.WithTransport(&football.Replayer{Dir: filepath.Join("testdata", "fixtures", phase)}),
+		football.WithLimiter(nil))
+
+	return syncer.NewSyncer(storage, notifier, syncer.Config{
+		Clock:    clock.Fixed(now),
+		Provider: provider,
+	})
+}
+
 func TestSyncer_ProcessPredictions(t *testing.T) {
-	storage, cleanup := setupTestDB(t)
-	defer cleanup()
+	storage := setupTestDB(t)
+	ctx := context.Background()
 
 	mockNotifier := new(MockNotifier)
-
-	sync := syncer.NewSyncer(storage, mockNotifier, "http://test.api.url", "test_api_key")
-
-	user := db.User{
-		ID:                 "user1",
-		Username:           "testuser",
-		ChatID:             123456789,
-		CreatedAt:          time.Now(),
-		TotalPredictions:   0,
-		CorrectPredictions: 0,
-		CurrentWinStreak:   0,
-		LongestWinStreak:   0,
+	mockNotifier.On("SendTextNotification", mock.Anything).Return(nil).Maybe()
+
+	sync := syncer.NewSyncer(storage, mockNotifier, syncer.Config{
+		Clock:    clock.Fixed(time.Now()),
+		Provider: football.NewFile(t.TempDir()),
+	})
+	require.NoError(t, sync.ManageSeasons(ctx))
+
+	for _, team := range []db.Team{
+		{ID: "team1", Name: "Team A", ShortName: "TA", Abbreviation: "TMA", CrestURL: "http://crest.url/ta.png", Country: "ENG"},
+		{ID: "team2", Name: "Team B", ShortName: "TB", Abbreviation: "TMB", CrestURL: "http://crest.url/tb.png", Country: "ENG"},
+	} {
+		require.NoError(t, storage.SaveTeam(ctx, team))
 	}
-	err := storage.CreateUser(user)
-	assert.NoError(t, err)
 
-	match := db.Match{
+	require.NoError(t, storage.SaveMatch(ctx, db.Match{
 		ID:         "match1",
 		Tournament: "Premier League",
 		HomeTeamID: "team1",
@@ -72,111 +114,111 @@ func TestSyncer_ProcessPredictions(t *testing.T) {
 		Status:     db.MatchStatusCompleted,
 		HomeScore:  intPtr(2),
 		AwayScore:  intPtr(1),
-	}
-	err = storage.SaveMatch(context.Background(), match)
-	assert.NoError(t, err)
-
-	team1 := db.Team{
-		ID:           "team1",
-		Name:         "Team A",
-		ShortName:    "TA",
-		Abbreviation: "TMA",
-		CrestURL:     "http://crest.url/ta.png",
-		Country:      "ENG",
-	}
-	team2 := db.Team{
-		ID:           "team2",
-		Name:         "Team B",
-		ShortName:    "TB",
-		Abbreviation: "TMB",
-		CrestURL:     "http://crest.url/tb.png",
-		Country:      "ENG",
-	}
-	err = storage.SaveTeam(context.Background(), team1)
-	assert.NoError(t, err)
-	err = storage.SaveTeam(context.Background(), team2)
-	assert.NoError(t, err)
+	}))
 
-	// Pre-populate predictions
 	predictions := []db.Prediction{
-		// Exact correct prediction
-		{
-			MatchID:            "match1",
-			UserID:             "user1",
-			PredictedHomeScore: intPtr(2),
-			PredictedAwayScore: intPtr(1),
-			PredictedOutcome:   stringPtr(db.MatchOutcomeHome),
-			PointsAwarded:      0,
-		},
-		// Correct outcome but incorrect score
-		{
-			MatchID:            "match1",
-			UserID:             "user1",
-			PredictedHomeScore: intPtr(1),
-			PredictedAwayScore: intPtr(0),
-			PredictedOutcome:   stringPtr(db.MatchOutcomeHome),
-			PointsAwarded:      0,
-		},
-		// Incorrect prediction
-		{
-			MatchID:            "match1",
-			UserID:             "user1",
-			PredictedHomeScore: intPtr(0),
-			PredictedAwayScore: intPtr(2),
-			PredictedOutcome:   stringPtr(db.MatchOutcomeAway),
-			PointsAwarded:      0,
-		},
+		// Exact score
+		{UserID: "user1", MatchID: "match1", PredictedHomeScore: intPtr(2), PredictedAwayScore: intPtr(1)},
+		// Correct outcome
+		{UserID: "user2", MatchID: "match1", PredictedOutcome: stringPtr(db.MatchOutcomeHome)},
+		// Wrong outcome
+		{UserID: "user3", MatchID: "match1", PredictedOutcome: stringPtr(db.MatchOutcomeAway)},
 	}
 
-	for _, p := range predictions {
-		err := storage.SavePrediction(context.Background(), p)
-		assert.NoError(t, err)
+	for i, p := range predictions {
+		require.NoError(t, storage.CreateUser(db.User{ID: p.UserID, Username: p.UserID, ChatID: int64(i + 1)}))
+		require.NoError(t, storage.SavePrediction(ctx, p))
 	}
 
-	// Mock notifier expectations
-	// Expect notifications for streak achievements if applicable
-	// For this test, assuming streaks are not long enough to trigger bonuses
-	// If exact predictions influence streaks, adjust accordingly
-	// For simplicity, not expecting notifications here
-	mockNotifier.On("SendTextNotification", mock.Anything).Return(nil)
-
-	// Run ProcessPredictions
-	err = sync.ProcessPredictions(context.Background())
-	assert.NoError(t, err)
-
-	// Verify that predictions have updated ResultPoints
-	updatedPredictions, err := storage.GetPredictionsForMatch(context.Background(), "match1")
-	assert.NoError(t, err)
-	assert.Len(t, updatedPredictions, 3)
-
-	for _, p := range updatedPredictions {
-		switch {
-		case *p.PredictedHomeScore == 2 && *p.PredictedAwayScore == 1:
-			// Exact correct prediction
-			assert.Equal(t, 7, p.PointsAwarded)
-		case *p.PredictedOutcome == db.MatchOutcomeHome:
-			// Correct outcome but incorrect score
-			assert.Equal(t, 3, p.PointsAwarded)
-		default:
-			// Incorrect prediction
-			assert.Equal(t, 0, p.PointsAwarded)
-		}
+	require.NoError(t, sync.ProcessPredictions(ctx))
+
+	settled, err := storage.GetPredictionsForMatch(ctx, "match1")
+	require.NoError(t, err)
+	assert.Len(t, settled, 3)
+
+	points := make(map[string]int)
+	for _, p := range settled {
+		points[p.UserID] = p.PointsAwarded
+	}
+	assert.Equal(t, map[string]int{"user1": 7, "user2": 3, "user3": 0}, points)
+
+	user, err := storage.GetUserByID("user1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, user.TotalPredictions)
+	assert.Equal(t, 1, user.CorrectPredictions)
+	assert.Equal(t, 1, user.CurrentWinStreak)
+	assert.Equal(t, 1, user.LongestWinStreak)
+
+	user, err = storage.GetUserByID("user3")
+	require.NoError(t, err)
+	assert.Equal(t, 1, user.TotalPredictions)
+	assert.Equal(t, 0, user.CorrectPredictions)
+	assert.Equal(t, 0, user.CurrentWinStreak)
+}
+
+// TestSyncer_ReplayMatchday syncs the opening matchday of the 2024/25 Premier League
+// from recorded responses: fixtures before kickoff, then results after the final whistle.
+func TestSyncer_ReplayMatchday(t *testing.T) {
+	storage := setupTestDB(t)
+	ctx := context.Background()
+
+	mockNotifier := new(MockNotifier)
+	mockNotifier.On("SendTextNotification", mock.Anything).Return(nil).Maybe()
+
+	require.NoError(t, storage.SeedCompetitions(ctx, []db.Competition{
+		{Code: "PL", Name: "Premier League", Enabled: true},
+	}))
+
+	kickoff := newReplaySyncer(storage, mockNotifier, "kickoff", time.Date(2024, 8, 16, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, kickoff.ManageSeasons(ctx))
+	require.NoError(t, kickoff.SyncTeams(ctx))
+	require.NoError(t, kickoff.SyncMatches(ctx))
+
+	teams, err := storage.ListTeams(ctx)
+	require.NoError(t, err)
+	assert.Len(t, teams, 8)
+
+	match, err := storage.GetMatchByID(ctx, "497410")
+	require.NoError(t, err)
+	assert.Equal(t, db.MatchStatusScheduled, match.Status)
+	assert.Equal(t, "Manchester United FC", match.HomeTeam.Name)
+	assert.Equal(t, "Fulham FC", match.AwayTeam.Name)
+	assert.Nil(t, match.HomeScore)
+
+	for i, p := range []db.Prediction{
+		{UserID: "exact", MatchID: "497410", PredictedHomeScore: intPtr(1), PredictedAwayScore: intPtr(0)},
+		{UserID: "outcome", MatchID: "497410", PredictedOutcome: stringPtr(db.MatchOutcomeHome)},
+		{UserID: "wrong", MatchID: "497410", PredictedOutcome: stringPtr(db.MatchOutcomeAway)},
+	} {
+		require.NoError(t, storage.CreateUser(db.User{ID: p.UserID, Username: p.UserID, ChatID: int64(i + 1)}))
+		require.NoError(t, storage.SavePrediction(ctx, p))
 	}
 
-	// Verify that user's points and streaks are updated
-	updatedUser, err := storage.GetUserByID("user1")
-	assert.NoError(t, err)
-	// Total predictions: 3
-	assert.Equal(t, 3, updatedUser.TotalPredictions)
-	// Correct predictions: 2
-	assert.Equal(t, 2, updatedUser.CorrectPredictions)
-	// Current streak: 2 (assuming correct predictions are consecutive)
-	assert.Equal(t, 2, updatedUser.CurrentWinStreak)
-	// Longest streak: 2
-	assert.Equal(t, 2, updatedUser.LongestWinStreak)
-
-	// Verify that notifier was not called (since streak < 4)
-	mockNotifier.AssertNotCalled(t, "SendTextNotification")
+	// Finished matches are settled by the sync itself
+	fulltime := newReplaySyncer(storage, mockNotifier, "fulltime", time.Date(2024, 8, 17, 20, 0, 0, 0, time.UTC))
+	require.NoError(t, fulltime.SyncMatches(ctx))
+
+	match, err = storage.GetMatchByID(ctx, "497410")
+	require.NoError(t, err)
+	assert.Equal(t, db.MatchStatusCompleted, match.Status)
+	assert.Equal(t, 1, *match.HomeScore)
+	assert.Equal(t, 0, *match.AwayScore)
+
+	settled, err := storage.GetPredictionsForMatch(ctx, "497410")
+	require.NoError(t, err)
+
+	points := make(map[string]int)
+	for _, p := range settled {
+		points[p.UserID] = p.PointsAwarded
+	}
+	assert.Equal(t, map[string]int{"exact": 7, "outcome": 3, "wrong": 0}, points)
+
+	// The table follows finished matches
+	standings, err := storage.GetStandings(ctx, "PL")
+	require.NoError(t, err)
+	require.Len(t, standings, 8)
+	assert.Equal(t, "Brighton & Hove Albion FC", standings[0].Team.Name)
+	assert.Equal(t, 3, standings[0].Points)
 }
 
 func intPtr(i int) *int {
@@ -0,0 +1,89 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/user/project/internal/db"
+	"github.com/user/project/internal/football"
+	"log"
+)
+
+// resolveTeam finds the stored team for a match side or table row (matchID is empty then): by provider ID first, then by name or alias.
+// Teams seen for the first time are created from the match payload; whatever can't be
+// resolved ends up in the unresolved teams report.
+func (s *Syncer) resolveTeam(ctx context.Context, competition, matchID string, t football.MatchTeam) (db.Team, error) {
+	if t.ID != "" {
+		team, err := s.storage.GetTeamByID(ctx, t.ID)
+		if err == nil {
+			if t.Name != "" && t.Name != team.Name {
+				s.rememberTeamAlias(ctx, team.ID, t.Name)
+			}
+			return team, nil
+		} else if !errors.Is(err, db.ErrNotFound) {
+			return db.Team{}, err
+		}
+	}
+
+	team, err := s.storage.GetTeamByName(ctx, t.Name)
+	if err == nil {
+		return team, nil
+	} else if !errors.Is(err, db.ErrNotFound) {
+		return db.Team{}, err
+	}
+
+	if t.ID == "" {
+		err = errors.New("team has no provider ID and matches no known name")
+	} else {
+		err = s.storage.SaveTeam(ctx, db.Team{
+			ID:           t.ID,
+			Name:         t.Name,
+			ShortName:    t.ShortName,
+			Abbreviation: t.TLA,
+			CrestURL:     t.Crest,
+		})
+		if err == nil {
+			log.Printf("Created team %s (%s) from match %s", t.ID, t.Name, matchID)
+			if err := s.storage.ResolveTeam(ctx, t.Name); err != nil {
+				log.Printf("Failed to clear unresolved team %q: %v", t.Name, err)
+			}
+			return s.storage.GetTeamByID(ctx, t.ID)
+		}
+	}
+
+	unresolved := db.UnresolvedTeam{
+		Name:        t.Name,
+		Competition: competition,
+	}
+	if matchID != "" {
+		unresolved.LastMatchID = &matchID
+	}
+	if t.ID != "" {
+		unresolved.ProviderTeamID = &t.ID
+	}
+	msg := err.Error()
+	unresolved.LastError = &msg
+
+	if recordErr := s.storage.RecordUnresolvedTeam(ctx, unresolved); recordErr != nil {
+		log.Printf("Failed to record unresolved team %q: %v", t.Name, recordErr)
+	}
+
+	return db.Team{}, fmt.Errorf("failed to resolve team %q: %w", t.Name, err)
+}
+
+// rememberTeamAlias keeps the provider's spelling of a team name so name lookups keep
+// working after renames. Every match of the team carries it, so it's only written once.
+func (s *Syncer) rememberTeamAlias(ctx context.Context, teamID, alias string) {
+	exists, err := s.storage.HasTeamAlias(ctx, teamID, alias)
+	if err != nil {
+		log.Printf("Failed to check alias %q for team %s: %v", alias, teamID, err)
+		return
+	}
+	if exists {
+		return
+	}
+
+	if err := s.storage.AddTeamAlias(ctx, teamID, alias); err != nil {
+		log.Printf("Failed to add alias %q for team %s: %v", alias, teamID, err)
+	}
+}
@@ -65,3 +65,11 @@ func Unauthorized(err error, message string) *Error {
 		Message: message,
 	}
 }
+
+func TooManyRequests(err error, message string) *Error {
+	return &Error{
+		Code:    http.StatusTooManyRequests,
+		Err:     err,
+		Message: message,
+	}
+}
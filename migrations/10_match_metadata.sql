@@ -0,0 +1,12 @@
+-- Полные данные матча от провайдера: тур, стадия, группа, судьи, турнир и регион
+ALTER TABLE matches ADD COLUMN competition_code TEXT;
+ALTER TABLE matches ADD COLUMN competition_emblem TEXT;
+ALTER TABLE matches ADD COLUMN area_code TEXT;
+ALTER TABLE matches ADD COLUMN area_name TEXT;
+ALTER TABLE matches ADD COLUMN matchday INTEGER;   -- Номер тура, NULL для турниров без туров
+ALTER TABLE matches ADD COLUMN stage TEXT;         -- REGULAR_SEASON, GROUP_STAGE, LAST_16, FINAL, ...
+ALTER TABLE matches ADD COLUMN group_name TEXT;    -- GROUP_A, ... для групповых этапов
+ALTER TABLE matches ADD COLUMN referees TEXT;      -- JSON массив судей
+
+CREATE INDEX idx_matches_competition_matchday ON matches (competition_code, matchday);
+CREATE INDEX idx_matches_stage ON matches (stage);
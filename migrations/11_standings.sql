@@ -0,0 +1,22 @@
+-- Турнирные таблицы
+CREATE TABLE standings
+(
+    competition_code TEXT    NOT NULL,
+    group_name       TEXT    NOT NULL DEFAULT '', -- Пусто для лиг с одной таблицей
+    team_id          TEXT    NOT NULL,
+    position         INTEGER NOT NULL,
+    played           INTEGER NOT NULL DEFAULT 0,
+    won              INTEGER NOT NULL DEFAULT 0,
+    draw             INTEGER NOT NULL DEFAULT 0,
+    lost             INTEGER NOT NULL DEFAULT 0,
+    goals_for        INTEGER NOT NULL DEFAULT 0,
+    goals_against    INTEGER NOT NULL DEFAULT 0,
+    goal_difference  INTEGER NOT NULL DEFAULT 0,
+    points           INTEGER NOT NULL DEFAULT 0,
+    form             TEXT,                        -- Последние результаты, например "W,D,L,W,W"
+    updated_at       DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    PRIMARY KEY (competition_code, group_name, team_id)
+);
+
+CREATE INDEX idx_standings_team ON standings (team_id);
@@ -0,0 +1,3 @@
+-- Индексы для формы команд и личных встреч
+CREATE INDEX idx_matches_home_team_date ON matches (home_team_id, away_team_id, match_date);
+CREATE INDEX idx_matches_away_team_date ON matches (away_team_id, match_date);
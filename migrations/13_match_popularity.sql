@@ -0,0 +1,2 @@
+-- Индекс для подсчёта прогнозов по матчу при пересчёте популярности
+CREATE INDEX idx_predictions_match_id ON predictions (match_id);
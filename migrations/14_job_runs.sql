@@ -0,0 +1,19 @@
+-- История запусков фоновых задач
+CREATE TABLE job_runs
+(
+    id                  TEXT PRIMARY KEY,
+    job                 TEXT     NOT NULL,                   -- Например team_sync, match_sync, settlement
+    status              TEXT     NOT NULL DEFAULT 'running', -- running, succeeded, failed
+    started_at          DATETIME NOT NULL,
+    finished_at         DATETIME,
+    matches_upserted    INTEGER  NOT NULL DEFAULT 0,
+    predictions_settled INTEGER  NOT NULL DEFAULT 0,
+    error               TEXT
+);
+
+CREATE INDEX idx_job_runs_started_at ON job_runs (started_at);
+CREATE INDEX idx_job_runs_job_started_at ON job_runs (job, started_at);
+
+-- Последняя ошибка синхронизации турнира, очищается при успешной синхронизации
+ALTER TABLE competitions ADD COLUMN last_sync_error TEXT;
+ALTER TABLE competitions ADD COLUMN last_sync_error_at DATETIME;
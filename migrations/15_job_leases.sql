@@ -0,0 +1,7 @@
+-- Аренда лидерства планировщика, чтобы задачи выполняла только одна реплика
+CREATE TABLE job_leases
+(
+    name       TEXT PRIMARY KEY,
+    holder     TEXT     NOT NULL, -- Идентификатор реплики
+    expires_at DATETIME NOT NULL
+);
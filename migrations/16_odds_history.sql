@@ -0,0 +1,18 @@
+-- История коэффициентов матча, новая запись только когда коэффициенты изменились
+CREATE TABLE odds_snapshots
+(
+    id          TEXT PRIMARY KEY,
+    match_id    TEXT     NOT NULL,
+    home_odds   REAL,
+    draw_odds   REAL,
+    away_odds   REAL,
+    recorded_at DATETIME NOT NULL,
+    FOREIGN KEY (match_id) REFERENCES matches (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_odds_snapshots_match_id_recorded_at ON odds_snapshots (match_id, recorded_at);
+
+-- Коэффициенты матча на момент сохранения прогноза
+ALTER TABLE predictions ADD COLUMN home_odds REAL;
+ALTER TABLE predictions ADD COLUMN draw_odds REAL;
+ALTER TABLE predictions ADD COLUMN away_odds REAL;
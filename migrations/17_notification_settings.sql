@@ -0,0 +1,15 @@
+-- Настройки уведомлений пользователя, без записи действуют значения по умолчанию
+CREATE TABLE notification_settings
+(
+    user_id           TEXT PRIMARY KEY,
+    favorite_team     BOOLEAN  NOT NULL DEFAULT 1, -- Напоминания о матчах любимой команды
+    streaks           BOOLEAN  NOT NULL DEFAULT 1, -- Серии угаданных прогнозов
+    results           BOOLEAN  NOT NULL DEFAULT 1, -- Итоги прогнозов и полученные награды
+    recaps            BOOLEAN  NOT NULL DEFAULT 1, -- Еженедельные итоги
+    marketing         BOOLEAN  NOT NULL DEFAULT 1, -- Рассылки и новости
+    quiet_hours_start TEXT,                        -- HH:MM в часовом поясе пользователя
+    quiet_hours_end   TEXT,
+    timezone          TEXT,                        -- Часовой пояс IANA, по умолчанию часовой пояс лиги
+    updated_at        DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
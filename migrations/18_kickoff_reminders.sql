@@ -0,0 +1,2 @@
+-- Напоминания перед началом матчей, которые пользователь обычно прогнозирует
+ALTER TABLE notification_settings ADD COLUMN reminders BOOLEAN NOT NULL DEFAULT 1;
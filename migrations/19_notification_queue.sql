@@ -0,0 +1,17 @@
+-- Уведомления, отложенные до конца тихих часов пользователя
+CREATE TABLE notification_queue
+(
+    id            TEXT PRIMARY KEY,
+    user_id       TEXT     NOT NULL,
+    kind          TEXT     NOT NULL, -- Вид уведомления из notification_settings
+    message       TEXT     NOT NULL,
+    web_app_url   TEXT,
+    button_text   TEXT,
+    image         BLOB,
+    deliver_after DATETIME NOT NULL, -- Конец тихих часов
+    expires_at    DATETIME,          -- Позже не отправляем, например напоминание после начала матча
+    created_at    DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notification_queue_deliver_after ON notification_queue (deliver_after);
@@ -0,0 +1,38 @@
+-- Индекс поиска хранил rowid пользователей, а у users первичный ключ TEXT:
+-- VACUUM может перенумеровать rowid и рассинхронизировать индекс.
+-- Теперь индекс хранит свою копию полей и связан с users по id.
+DROP TRIGGER IF EXISTS users_fts_insert;
+DROP TRIGGER IF EXISTS users_fts_delete;
+DROP TRIGGER IF EXISTS users_fts_update;
+DROP TABLE IF EXISTS users_fts;
+
+CREATE VIRTUAL TABLE users_fts USING fts5
+(
+    username,
+    first_name,
+    last_name,
+    user_id UNINDEXED,
+    tokenize = 'trigram'
+);
+
+INSERT INTO users_fts (username, first_name, last_name, user_id)
+SELECT username, first_name, last_name, id
+FROM users;
+
+CREATE TRIGGER users_fts_insert AFTER INSERT ON users
+BEGIN
+    INSERT INTO users_fts (username, first_name, last_name, user_id)
+    VALUES (new.username, new.first_name, new.last_name, new.id);
+END;
+
+CREATE TRIGGER users_fts_delete AFTER DELETE ON users
+BEGIN
+    DELETE FROM users_fts WHERE user_id = old.id;
+END;
+
+CREATE TRIGGER users_fts_update AFTER UPDATE OF id, username, first_name, last_name ON users
+BEGIN
+    UPDATE users_fts
+    SET username = new.username, first_name = new.first_name, last_name = new.last_name, user_id = new.id
+    WHERE user_id = old.id;
+END;
@@ -0,0 +1,8 @@
+-- Последние матчи команды дома: индекс (home_team_id, away_team_id, match_date)
+-- не дает читать их по дате, между командой и датой стоит соперник
+CREATE INDEX idx_matches_home_date ON matches (home_team_id, match_date);
+
+-- Даты матчей сравниваются как текст, приводим сохраненные ранее к UTC
+UPDATE matches
+SET match_date = datetime(match_date) || '+00:00'
+WHERE match_date != datetime(match_date) || '+00:00';
@@ -0,0 +1,5 @@
+-- Сезон турнира у провайдера: номера туров начинаются заново каждый сезон
+ALTER TABLE matches ADD COLUMN competition_season TEXT;
+
+DROP INDEX idx_matches_competition_matchday;
+CREATE INDEX idx_matches_competition_season_matchday ON matches (competition_code, competition_season, matchday);
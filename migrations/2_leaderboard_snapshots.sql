@@ -0,0 +1,14 @@
+-- Ежедневные снимки позиций в лидерборде
+CREATE TABLE leaderboard_snapshots
+(
+    user_id       TEXT    NOT NULL,
+    season_id     TEXT    NOT NULL,
+    snapshot_date DATE    NOT NULL, -- День, за который сделан снимок (YYYY-MM-DD)
+    position      INTEGER NOT NULL,
+    points        INTEGER NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY (season_id) REFERENCES seasons (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, season_id, snapshot_date)
+);
+
+CREATE INDEX idx_leaderboard_snapshots_season_date ON leaderboard_snapshots (season_id, snapshot_date);
@@ -0,0 +1,12 @@
+-- Значки, которые выдает движок достижений
+ALTER TABLE badges ADD COLUMN description TEXT;
+
+INSERT INTO badges (id, name, color, icon, description)
+VALUES ('first_exact_score', 'Sniper', '#F59E0B', '🎯', 'Predict the exact score of a match'),
+       ('win_streak_5', 'On Fire', '#EF4444', '🔥', 'Get 5 predictions right in a row'),
+       ('win_streak_10', 'Unstoppable', '#DC2626', '⚡', 'Get 10 predictions right in a row'),
+       ('win_streak_20', 'Oracle', '#7C3AED', '🔮', 'Get 20 predictions right in a row'),
+       ('predictions_100', 'Centurion', '#2563EB', '💯', 'Make 100 predictions'),
+       ('season_top_10', 'Top 10', '#10B981', '🏆', 'Finish a season in the top 10'),
+       ('full_matchday', 'Completionist', '#0EA5E9', '📅', 'Predict every match of a matchday')
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,6 @@
+-- Настройка приватности профиля: public, followers, private
+ALTER TABLE users ADD COLUMN privacy TEXT DEFAULT 'public';
+
+CREATE INDEX idx_user_followers_following_id ON user_followers (following_id);
+CREATE INDEX idx_predictions_user_id_created_at ON predictions (user_id, created_at);
+CREATE INDEX idx_user_badges_user_id_awarded_at ON user_badges (user_id, awarded_at);
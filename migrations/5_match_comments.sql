@@ -0,0 +1,37 @@
+-- Обсуждение матчей
+CREATE TABLE match_comments
+(
+    id         TEXT PRIMARY KEY,
+    match_id   TEXT NOT NULL,
+    user_id    TEXT NOT NULL,
+    body       TEXT NOT NULL,
+    is_spoiler BOOLEAN  DEFAULT 0, -- Содержит прогноз на счет, скрывается до начала матча
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (match_id) REFERENCES matches (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_match_comments_match_id_created_at ON match_comments (match_id, created_at);
+CREATE INDEX idx_match_comments_user_id_created_at ON match_comments (user_id, created_at);
+
+CREATE TABLE comment_reactions
+(
+    comment_id TEXT NOT NULL,
+    user_id    TEXT NOT NULL,
+    emoji      TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (comment_id, user_id, emoji),
+    FOREIGN KEY (comment_id) REFERENCES match_comments (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE comment_reports
+(
+    comment_id TEXT NOT NULL,
+    user_id    TEXT NOT NULL,
+    reason     TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (comment_id, user_id),
+    FOREIGN KEY (comment_id) REFERENCES match_comments (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
@@ -0,0 +1,35 @@
+-- Полнотекстовый поиск пользователей по username и имени.
+-- Триграммы дают поиск по подстроке и нечеткое совпадение при опечатках.
+CREATE VIRTUAL TABLE users_fts USING fts5
+(
+    username,
+    first_name,
+    last_name,
+    content = 'users',
+    content_rowid = 'rowid',
+    tokenize = 'trigram'
+);
+
+INSERT INTO users_fts (users_fts) VALUES ('rebuild');
+
+CREATE TRIGGER users_fts_insert AFTER INSERT ON users
+BEGIN
+    INSERT INTO users_fts (rowid, username, first_name, last_name)
+    VALUES (new.rowid, new.username, new.first_name, new.last_name);
+END;
+
+CREATE TRIGGER users_fts_delete AFTER DELETE ON users
+BEGIN
+    INSERT INTO users_fts (users_fts, rowid, username, first_name, last_name)
+    VALUES ('delete', old.rowid, old.username, old.first_name, old.last_name);
+END;
+
+CREATE TRIGGER users_fts_update AFTER UPDATE OF username, first_name, last_name ON users
+BEGIN
+    INSERT INTO users_fts (users_fts, rowid, username, first_name, last_name)
+    VALUES ('delete', old.rowid, old.username, old.first_name, old.last_name);
+    INSERT INTO users_fts (rowid, username, first_name, last_name)
+    VALUES (new.rowid, new.username, new.first_name, new.last_name);
+END;
+
+CREATE INDEX idx_users_favorite_team ON users (favorite_team_id);
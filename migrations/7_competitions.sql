@@ -0,0 +1,12 @@
+-- Турниры, которые синхронизируются с провайдером данных
+CREATE TABLE competitions
+(
+    code          TEXT PRIMARY KEY,          -- Код турнира у провайдера (PL, CL, ...)
+    name          TEXT    NOT NULL,
+    emblem        TEXT,
+    area          TEXT,
+    enabled       BOOLEAN NOT NULL DEFAULT 1,
+    sync_priority INTEGER NOT NULL DEFAULT 0, -- Турниры с большим приоритетом синхронизируются первыми
+    created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at    DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,4 @@
+-- Состояние инкрементальной синхронизации матчей по турнирам
+ALTER TABLE competitions ADD COLUMN sync_cursor DATETIME;       -- Наибольший lastUpdated среди сохраненных матчей
+ALTER TABLE competitions ADD COLUMN last_synced_at DATETIME;    -- Последняя синхронизация окна дат
+ALTER TABLE competitions ADD COLUMN last_full_sync_at DATETIME; -- Последняя полная сверка сезона
@@ -0,0 +1,24 @@
+-- Альтернативные названия команд, по которым матчи провайдера сопоставляются с командами
+CREATE TABLE team_aliases
+(
+    alias      TEXT PRIMARY KEY,
+    team_id    TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_team_aliases_team_id ON team_aliases (team_id);
+
+-- Команды из матчей, которые не удалось сопоставить или создать
+CREATE TABLE unresolved_teams
+(
+    name             TEXT    NOT NULL,
+    competition      TEXT    NOT NULL,
+    provider_team_id TEXT,
+    last_match_id    TEXT,
+    last_error       TEXT,
+    occurrences      INTEGER NOT NULL DEFAULT 1,
+    first_seen_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at     DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (name, competition)
+);